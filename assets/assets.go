@@ -10,3 +10,6 @@ var CSS embed.FS
 
 //go:embed templates/* templates/**/*
 var Templates embed.FS
+
+//go:embed zola/*
+var Zola embed.FS
@@ -0,0 +1,39 @@
+// @ts-check
+
+/** @type {import('@docusaurus/types').Config} */
+const config = {
+  title: '{{.Title}}',
+  url: '{{.URL}}',
+  baseUrl: '{{.BaseURL}}',
+
+  onBrokenLinks: 'throw',
+  onBrokenMarkdownLinks: 'warn',
+
+  presets: [
+    [
+      'classic',
+      {
+        docs: {
+          routeBasePath: '/',
+          sidebarPath: './sidebars.js',
+        },
+        blog: false,
+      },
+    ],
+  ],
+
+  themeConfig: {
+    navbar: {
+      title: '{{.Title}}',
+      items: [
+        {
+          href: '{{.Repo}}',
+          label: 'GitHub',
+          position: 'right',
+        },
+      ],
+    },
+  },
+};
+
+export default config;
@@ -0,0 +1,15 @@
+// @ts-check
+
+// Sidebar categories are generated by Modo, using files '_category_.json'.
+
+/** @type {import('@docusaurus/plugin-content-docs').SidebarsConfig} */
+const sidebars = {
+  docs: [
+    {
+      type: 'autogenerated',
+      dirName: '.',
+    },
+  ],
+};
+
+export default sidebars;
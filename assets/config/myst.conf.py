@@ -0,0 +1,13 @@
+# Sphinx configuration, see https://www.sphinx-doc.org/en/master/usage/configuration.html
+
+project = "{{.Title}}"
+
+extensions = ["myst_parser"]
+
+source_suffix = {".md": "markdown"}
+exclude_patterns = ["_build"]
+
+# Required for links to sections, like methods (level 3) and their overloads (level 4).
+myst_heading_anchors = 4
+
+html_theme = "alabaster"
@@ -70,10 +70,13 @@ Complete documentation at https://mlange-42.github.io/modo/`,
 	root.Flags().StringSliceP("input", "i", []string{}, "'mojo doc' JSON file to process. Reads from STDIN if not specified.\nIf a single directory is given, it is processed recursively")
 	root.Flags().StringP("output", "o", "", "Output folder for generated Markdown files")
 	root.Flags().StringP("tests", "t", "", "Target folder to extract doctests for 'mojo test'.\nSee also command 'modo test' (default no doctests)")
-	root.Flags().StringP("format", "f", "plain", "Output format. One of (plain|mdbook|hugo)")
+	root.Flags().StringP("format", "f", "plain", "Output format. One of (plain|mdbook|hugo|mkdocs|docusaurus|zola|html|myst|wiki|man),\nor 'plugin:<path>' for a formatter plugin executable")
 	root.Flags().BoolP("exports", "e", false, "Process according to 'Exports:' sections in packages")
 	root.Flags().BoolP("short-links", "s", false, "Render shortened link labels, stripping packages and modules")
+	root.Flags().BoolP("inherited-methods", "H", false, "List default-implemented trait methods on the pages of structs that don't override them")
 	root.Flags().BoolP("report-missing", "M", false, "Report missing docstings and coverage")
+	root.Flags().BoolP("search-index", "I", false, "Write a search index 'search-index.json' with all members to the output folder")
+	root.Flags().BoolP("inventory", "O", false, "Write a link inventory 'objects.json' with all members to the output folder")
 	root.Flags().BoolP("case-insensitive", "C", false, "Build for systems that are not case-sensitive regarding file names.\nAppends hyphen (-) to capitalized file names")
 	root.Flags().BoolP("strict", "S", false, "Strict mode. Errors instead of warnings")
 	root.Flags().BoolP("dry-run", "D", false, "Dry-run without any file output. Disables post-processing scripts")
@@ -111,7 +114,15 @@ func runBuild(args *document.Config) error {
 		return err
 	}
 
-	if err := runFilesOrDir(runBuildOnce, args, formatter); err != nil {
+	tests := []*document.DocTestFile{}
+	err = runFilesOrDir(buildOnce(&tests), args, formatter)
+	if closeErr := format.Close(formatter); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := document.WriteDocTestManifest(args, tests); err != nil {
 		return err
 	}
 
@@ -124,22 +135,54 @@ func runBuild(args *document.Config) error {
 	return nil
 }
 
-func runBuildOnce(file string, args *document.Config, form document.Formatter, subdir string, isFile, isDir bool) error {
-	if isDir {
-		if err := document.ExtractTestsMarkdown(args, form, file, true); err != nil {
+// buildOnce creates a command that builds the docs and collects the written doctest files.
+func buildOnce(tests *[]*document.DocTestFile) command {
+	return func(file string, args *document.Config, form document.Formatter, subdir string, isFile, isDir bool) error {
+		if isDir {
+			// Prepare all packages first, so that Markdown pages can reference them.
+			procs := []*document.Processor{}
+			err := runDir(file, args, form, func(file string, args *document.Config, form document.Formatter, subdir string, isFile, isDir bool) error {
+				docs, err := readDocs(file)
+				if err != nil {
+					return err
+				}
+				proc, err := document.Prepare(docs, args, form, subdir)
+				if err != nil {
+					return err
+				}
+				procs = append(procs, proc)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			files, err := document.ExtractTestsMarkdown(args, form, file, true, procs...)
+			if err != nil {
+				return err
+			}
+			*tests = append(*tests, files...)
+			for _, proc := range procs {
+				if err := proc.Render(); err != nil {
+					return err
+				}
+				*tests = append(*tests, proc.TestFiles()...)
+			}
+			return nil
+		}
+		docs, err := readDocs(file)
+		if err != nil {
 			return err
 		}
-		return runDir(file, args, form, runBuildOnce)
-	}
-	docs, err := readDocs(file)
-	if err != nil {
-		return err
-	}
-	err = document.Render(docs, args, form, subdir)
-	if err != nil {
-		return err
+		proc, err := document.Prepare(docs, args, form, subdir)
+		if err != nil {
+			return err
+		}
+		if err := proc.Render(); err != nil {
+			return err
+		}
+		*tests = append(*tests, proc.TestFiles()...)
+		return nil
 	}
-	return nil
 }
 
 func runPreBuildCommands(cfg *document.Config) error {
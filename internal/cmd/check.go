@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/mlange-42/modo/internal/format"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func checkCommand(_ chan struct{}) (*cobra.Command, error) {
+	v := viper.New()
+	var config string
+
+	var cwd string
+
+	root := &cobra.Command{
+		Use:   "check [PATH]",
+		Short: "Check links and anchors in the generated output",
+		Long: `Check links and anchors in the generated output.
+
+Scans all Markdown files in the output directory, including copied handwritten pages,
+and reports relative links to missing files or headings. Run after 'modo build'.
+
+Complete documentation at https://mlange-42.github.io/modo/`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if err = checkConfigFile(config); err != nil {
+				return err
+			}
+			if cwd, err = mountProject(v, config, args); err != nil {
+				return err
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			defer func() {
+				if err := os.Chdir(cwd); err != nil {
+					fmt.Println(err)
+				}
+			}()
+
+			start := time.Now()
+
+			cliArgs, err := document.ConfigFromViper(v)
+			if err != nil {
+				return err
+			}
+			if err := runCheck(cliArgs); err != nil {
+				return err
+			}
+
+			fmt.Printf("Completed in %.1fms 🧯\n", float64(time.Since(start).Microseconds())/1000.0)
+			return nil
+		},
+	}
+
+	root.Flags().StringVarP(&config, "config", "c", defaultConfigFile, "Config file in the working directory to use")
+	root.Flags().StringP("output", "o", "", "Output folder to check")
+	root.Flags().StringP("format", "f", "plain", "Output format. One of (plain|mdbook|hugo|mkdocs|docusaurus|zola|html|myst|wiki|man),\nor 'plugin:<path>' for a formatter plugin executable")
+
+	root.Flags().SortFlags = false
+	root.MarkFlagFilename("config", "yaml")
+	root.MarkFlagDirname("output")
+
+	for _, flag := range []string{"output", "format"} {
+		if err := v.BindPFlag(flag, root.Flags().Lookup(flag)); err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+
+func runCheck(args *document.Config) error {
+	if args.OutputDir == "" {
+		return fmt.Errorf("no output path given")
+	}
+
+	formatter, err := format.GetFormatter(args.RenderFormat)
+	if err != nil {
+		return err
+	}
+
+	broken, err := format.CheckLinks(args.OutputDir, formatter)
+	if err != nil {
+		return err
+	}
+	for _, link := range broken {
+		fmt.Println(link.String())
+	}
+	if len(broken) > 0 {
+		return fmt.Errorf("found %d broken link(s) in %s", len(broken), args.OutputDir)
+	}
+	fmt.Printf("No broken links found in %s.\n", args.OutputDir)
+	return nil
+}
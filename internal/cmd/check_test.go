@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	cmd, err := checkCommand(nil)
+	assert.Nil(t, err)
+	cmd.SetArgs([]string{"--output", "../../test/ref"})
+	err = cmd.Execute()
+	assert.Nil(t, err)
+
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(path.Join(dir, "_index.md"), []byte("# Index\n\nSee [missing](missing.md).\n"), 0644))
+
+	cmd, err = checkCommand(nil)
+	assert.Nil(t, err)
+	cmd.SetArgs([]string{"--output", dir})
+	err = cmd.Execute()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "found 1 broken link(s)")
+
+	cmd, err = checkCommand(nil)
+	assert.Nil(t, err)
+	cmd.SetArgs([]string{"--output", dir, "--format", "html"})
+	err = cmd.Execute()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "not supported for format 'html'")
+}
@@ -49,7 +49,7 @@ func initCommand(_ chan struct{}) (*cobra.Command, error) {
 		Short: "Set up a Modo project in the current directory",
 		Long: `Set up a Modo project in the current directory.
 
-The format argument is required and must be one of (plain|mdbook|hugo).
+The format argument is required and must be one of (plain|mdbook|hugo|mkdocs|docusaurus|zola|html|myst|wiki|man).
 Complete documentation at https://mlange-42.github.io/modo/`,
 		Args:         cobra.ExactArgs(1),
 		SilenceUsage: true,
@@ -95,7 +95,7 @@ func initProject(configFile string, initArgs *initArgs) error {
 	if err != nil {
 		return err
 	}
-	sources, warning, err := findSources(initArgs.Format)
+	sources, warning, err := findSources()
 	if err != nil {
 		return err
 	}
@@ -143,7 +143,7 @@ func initProject(configFile string, initArgs *initArgs) error {
 	return nil
 }
 
-func findSources(f string) ([]document.PackageSource, string, error) {
+func findSources() ([]document.PackageSource, string, error) {
 	warning := ""
 	sources := []document.PackageSource{}
 	srcExists, srcIsDir, err := util.FileExists(srcDir)
@@ -224,10 +224,6 @@ func findSources(f string) ([]document.PackageSource, string, error) {
 		sources = []document.PackageSource{{Name: "mypkg", Path: []string{srcDir, "mypkg"}}}
 		warning = fmt.Sprintf("WARNING: no package sources found; using %s", path.Join(sources[0].Path...))
 		fmt.Println(warning)
-	} else if f == "mdbook" && len(sources) > 1 {
-		warning = fmt.Sprintf("WARNING: mdbook format can only use a single package but %d were found; using %s", len(sources), path.Join(sources[0].Path...))
-		sources = sources[:1]
-		fmt.Println(warning)
 	}
 	return sources, warning, nil
 }
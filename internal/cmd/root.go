@@ -35,7 +35,7 @@ Complete documentation at https://mlange-42.github.io/modo/`,
 
 	root.CompletionOptions.HiddenDefaultCmd = true
 
-	for _, fn := range []func(chan struct{}) (*cobra.Command, error){initCommand, buildCommand, testCommand, cleanCommand} {
+	for _, fn := range []func(chan struct{}) (*cobra.Command, error){initCommand, buildCommand, testCommand, cleanCommand, showCommand, checkCommand} {
 		cmd, err := fn(nil)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/mlange-42/modo/internal/format"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func showCommand(_ chan struct{}) (*cobra.Command, error) {
+	v := viper.New()
+	var config string
+	var showFormat string
+
+	var cwd string
+
+	root := &cobra.Command{
+		Use:   "show MEMBER [PATH]",
+		Short: "Show the documentation of a single member in the terminal",
+		Long: `Show the documentation of a single member in the terminal.
+
+The member is given by its dotted path, like 'mypkg.mymod.MyStruct.method'.
+Uses the inputs from the 'modo.yaml' file in the current directory if no path is given.
+The flags listed below overwrite the settings from that file.
+
+Complete documentation at https://mlange-42.github.io/modo/`,
+		Example: `  modo show mypkg.mymod.MyStruct             # show a struct
+  modo show mypkg.mymod.MyStruct.method      # show a method
+  modo show -f man mypkg.mymod | man -l -    # show a module as man page`,
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if err = checkConfigFile(config); err != nil {
+				return err
+			}
+			if cwd, err = mountProject(v, config, args[1:]); err != nil {
+				return err
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			defer func() {
+				if err := os.Chdir(cwd); err != nil {
+					fmt.Println(err)
+				}
+			}()
+
+			cliArgs, err := document.ConfigFromViper(v)
+			if err != nil {
+				return err
+			}
+			return runShow(cliArgs, showFormat, args[0])
+		},
+	}
+
+	root.Flags().StringVarP(&config, "config", "c", defaultConfigFile, "Config file in the working directory to use")
+	root.Flags().StringSliceP("input", "i", []string{}, "'mojo doc' JSON file to process. Reads from STDIN if not specified.\nIf a single directory is given, it is processed recursively")
+	root.Flags().StringVarP(&showFormat, "format", "f", "plain", "Output format. One of (plain|man)")
+	root.Flags().BoolP("exports", "e", false, "Process according to 'Exports:' sections in packages")
+	root.Flags().BoolP("case-insensitive", "C", false, "Build for systems that are not case-sensitive regarding file names.\nAppends hyphen (-) to capitalized file names")
+	root.Flags().StringSliceP("templates", "T", []string{}, "Optional directories with templates for (partial) overwrite.\nSee folder assets/templates in the repository")
+
+	root.Flags().SortFlags = false
+	root.MarkFlagFilename("config", "yaml")
+	root.MarkFlagFilename("input", "json")
+	root.MarkFlagDirname("templates")
+
+	for _, flag := range []string{"input", "exports", "case-insensitive", "templates"} {
+		if err := v.BindPFlag(flag, root.Flags().Lookup(flag)); err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+
+func runShow(args *document.Config, showFormat string, member string) error {
+	if showFormat != "plain" && showFormat != "man" {
+		return fmt.Errorf("unsupported format '%s' for command show. Must be one of (plain|man)", showFormat)
+	}
+	formatter, err := format.GetFormatter(showFormat)
+	if err != nil {
+		return err
+	}
+	args.TestOutput = ""
+	args.DryRun = true
+
+	pkg, _, _ := strings.Cut(member, ".")
+	found := false
+
+	var showOnce command
+	showOnce = func(file string, args *document.Config, form document.Formatter, subdir string, isFile, isDir bool) error {
+		if found {
+			return nil
+		}
+		if isDir {
+			return runDir(file, args, form, showOnce)
+		}
+		docs, err := readDocs(file)
+		if err != nil {
+			return err
+		}
+		if docs.Decl.Name != pkg {
+			return nil
+		}
+		found = true
+		return document.Show(docs, args, form, member, os.Stdout)
+	}
+
+	if err := runFilesOrDir(showOnce, args, formatter); err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("package '%s' not found in the input files", pkg)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShow(t *testing.T) {
+	yml := `
+decl:
+  name: modo
+  kind: package
+  modules:
+    - name: mod1
+      kind: module
+      structs:
+        - name: Struct1
+          kind: struct
+          summary: A struct
+`
+	file := path.Join(t.TempDir(), "modo.yaml")
+	assert.Nil(t, os.WriteFile(file, []byte(yml), 0644))
+
+	cmd, err := showCommand(nil)
+	assert.Nil(t, err)
+	cmd.SetArgs([]string{"modo.mod1.Struct1", "--input", file})
+	err = cmd.Execute()
+	assert.Nil(t, err)
+
+	cmd, err = showCommand(nil)
+	assert.Nil(t, err)
+	cmd.SetArgs([]string{"modo.mod1.Struct1", "--input", file, "--format", "man"})
+	err = cmd.Execute()
+	assert.Nil(t, err)
+
+	cmd, err = showCommand(nil)
+	assert.Nil(t, err)
+	cmd.SetArgs([]string{"other.mod1.Struct1", "--input", file})
+	err = cmd.Execute()
+	assert.NotNil(t, err)
+
+	cmd, err = showCommand(nil)
+	assert.Nil(t, err)
+	cmd.SetArgs([]string{"modo.mod1.Struct1", "--input", file, "--format", "hugo"})
+	err = cmd.Execute()
+	assert.NotNil(t, err)
+}
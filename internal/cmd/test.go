@@ -1,20 +1,31 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
 	"time"
 
 	"github.com/mlange-42/modo/internal/document"
 	"github.com/mlange-42/modo/internal/format"
+	"github.com/mlange-42/modo/internal/util"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+const defaultTestCommand = "mojo test"
+
+var testFailureRegex = regexp.MustCompile(`Failure: '([^']+)::(\w+)\(\)'`)
+
 func testCommand(stopWatch chan struct{}) (*cobra.Command, error) {
 	v := viper.New()
 	var config string
 	var watch bool
+	var run bool
 
 	var cwd string
 
@@ -28,7 +39,8 @@ The flags listed below overwrite the settings from that file.
 
 Complete documentation at https://mlange-42.github.io/modo/`,
 		Example: `  modo init hugo                 # set up a project, e.g. for Hugo
-  modo test                      # extract doc-tests`,
+  modo test                      # extract doc-tests
+  modo test --run                # extract and run doc-tests`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -54,11 +66,14 @@ Complete documentation at https://mlange-42.github.io/modo/`,
 			if err != nil {
 				return err
 			}
-			if err := runTest(cliArgs); err != nil {
+			runFunc := func(args *document.Config) error {
+				return runTest(args, run)
+			}
+			if err := runFunc(cliArgs); err != nil {
 				return err
 			}
 			if watch {
-				return watchAndRun(cliArgs, runTest, stopWatch)
+				return watchAndRun(cliArgs, runFunc, stopWatch)
 			}
 
 			fmt.Printf("Completed in %.1fms 🧯\n", float64(time.Since(start).Microseconds())/1000.0)
@@ -69,6 +84,8 @@ Complete documentation at https://mlange-42.github.io/modo/`,
 	root.Flags().StringVarP(&config, "config", "c", defaultConfigFile, "Config file in the working directory to use")
 	root.Flags().StringSliceP("input", "i", []string{}, "'mojo doc' JSON file to process. Reads from STDIN if not specified.\nIf a single directory is given, it is processed recursively")
 	root.Flags().StringP("tests", "t", "", "Target folder to extract doctests for 'mojo test'")
+	root.Flags().BoolVarP(&run, "run", "R", false, "Run the extracted doctests with the test command, and report failures by member")
+	root.Flags().String("test-command", defaultTestCommand, "Command to run the doctests with. The tests folder is appended as argument")
 	root.Flags().BoolP("case-insensitive", "C", false, "Build for systems that are not case-sensitive regarding file names.\nAppends hyphen (-) to capitalized file names")
 	root.Flags().BoolP("strict", "S", false, "Strict mode. Errors instead of warnings")
 	root.Flags().BoolP("dry-run", "D", false, "Dry-run without any file output. Disables post-processing scripts")
@@ -89,7 +106,7 @@ Complete documentation at https://mlange-42.github.io/modo/`,
 	return root, nil
 }
 
-func runTest(args *document.Config) error {
+func runTest(args *document.Config, run bool) error {
 	if args.TestOutput == "" {
 		return fmt.Errorf("no output path for tests given")
 	}
@@ -100,10 +117,20 @@ func runTest(args *document.Config) error {
 		}
 	}
 
-	if err := runFilesOrDir(runTestOnce, args, nil); err != nil {
+	tests := []*document.DocTestFile{}
+	if err := runFilesOrDir(testOnce(&tests), args, nil); err != nil {
+		return err
+	}
+	if err := document.WriteDocTestManifest(args, tests); err != nil {
 		return err
 	}
 
+	if run && !args.DryRun {
+		if err := runDocTests(args, tests); err != nil {
+			return err
+		}
+	}
+
 	if !args.Bare && !args.DryRun {
 		if err := runPostTestCommands(args); err != nil {
 			return err
@@ -113,21 +140,30 @@ func runTest(args *document.Config) error {
 	return nil
 }
 
-func runTestOnce(file string, args *document.Config, _ document.Formatter, subdir string, isFile, isDir bool) error {
-	if isDir {
-		if err := document.ExtractTestsMarkdown(args, &format.Plain{}, file, false); err != nil {
+// testOnce creates a command that extracts doctests and collects the written test files.
+func testOnce(tests *[]*document.DocTestFile) command {
+	var runTestOnce command
+	runTestOnce = func(file string, args *document.Config, _ document.Formatter, subdir string, isFile, isDir bool) error {
+		if isDir {
+			files, err := document.ExtractTestsMarkdown(args, &format.Plain{}, file, false)
+			if err != nil {
+				return err
+			}
+			*tests = append(*tests, files...)
+			return runDir(file, args, nil, runTestOnce)
+		}
+		docs, err := readDocs(file)
+		if err != nil {
 			return err
 		}
-		return runDir(file, args, nil, runTestOnce)
-	}
-	docs, err := readDocs(file)
-	if err != nil {
-		return err
-	}
-	if err := document.ExtractTests(docs, args, &format.Plain{}, subdir); err != nil {
-		return err
+		files, err := document.ExtractTests(docs, args, &format.Plain{}, subdir)
+		if err != nil {
+			return err
+		}
+		*tests = append(*tests, files...)
+		return nil
 	}
-	return nil
+	return runTestOnce
 }
 
 func runPreTestCommands(cfg *document.Config) error {
@@ -149,3 +185,76 @@ func runPostTestCommands(cfg *document.Config) error {
 	}
 	return nil
 }
+
+// testFailure is a failed doctest, as reported by the test command.
+type testFailure struct {
+	File     string
+	Function string
+}
+
+// runDocTests runs the test command on the tests folder
+// and reports failed tests with the member or Markdown file they originate from.
+func runDocTests(args *document.Config, tests []*document.DocTestFile) error {
+	testCmd := args.TestCommand
+	if testCmd == "" {
+		testCmd = defaultTestCommand
+	}
+	dir := util.ShellQuote(args.TestOutput)
+	fmt.Printf("Running doctests: %s %s\n", testCmd, dir)
+
+	output := bytes.Buffer{}
+	cmd := exec.Command("bash", "-c", fmt.Sprintf("%s\n%s %s", setExitOnError, testCmd, dir))
+	cmd.Stdout = io.MultiWriter(os.Stdout, &output)
+	cmd.Stderr = io.MultiWriter(os.Stderr, &output)
+	cmdErr := cmd.Run()
+
+	failures := parseTestFailures(output.String())
+	if len(failures) == 0 {
+		if cmdErr != nil {
+			return fmt.Errorf("in test command: %s", cmdErr)
+		}
+		return nil
+	}
+
+	origins := map[string]*document.DocTestFile{}
+	for _, t := range tests {
+		if abs, err := filepath.Abs(t.File); err == nil {
+			origins[abs] = t
+		}
+	}
+	fmt.Println()
+	for _, f := range failures {
+		fmt.Println(failureMessage(f, origins))
+	}
+	return fmt.Errorf("%d doctest(s) failed", len(failures))
+}
+
+// parseTestFailures extracts the failed tests from the output of 'mojo test'.
+func parseTestFailures(output string) []testFailure {
+	failures := []testFailure{}
+	for _, m := range testFailureRegex.FindAllStringSubmatch(output, -1) {
+		failures = append(failures, testFailure{File: m[1], Function: m[2]})
+	}
+	return failures
+}
+
+// failureMessage describes a failed test, with the doctest's origin if it is known.
+func failureMessage(f testFailure, origins map[string]*document.DocTestFile) string {
+	file := f.File
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	t, ok := origins[file]
+	if !ok || t.Function != f.Function {
+		return fmt.Sprintf("FAIL %s::%s", f.File, f.Function)
+	}
+	origin := t.Member
+	if t.Markdown != "" {
+		origin = t.Markdown
+	}
+	msg := fmt.Sprintf("FAIL doctest '%s' in %s, line %d (%s)", t.Name, origin, t.Line, t.File)
+	if t.Raises {
+		msg += "\n     Only errors from the 'raises' blocks are expected. Errors in setup or teardown blocks fail the test, too."
+	}
+	return msg
+}
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"path"
 	"testing"
 
+	"github.com/mlange-42/modo/internal/document"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,3 +17,55 @@ func TestTest(t *testing.T) {
 	err = cmd.Execute()
 	assert.Nil(t, err)
 }
+
+func TestParseTestFailures(t *testing.T) {
+	output := `Total Discovered Tests: 2
+
+Passed : 1 (50.00%)
+Failed : 1 (50.00%)
+Skipped: 0 (0.00%)
+
+******************** Failure: '/tmp/doctest/pkg_mod_func_ex_test.mojo::test_ex()' ********************
+
+Unhandled exception caught during execution
+`
+	assert.Equal(t, []testFailure{
+		{File: "/tmp/doctest/pkg_mod_func_ex_test.mojo", Function: "test_ex"},
+	}, parseTestFailures(output))
+}
+
+func TestRunDocTests(t *testing.T) {
+	dir := t.TempDir()
+	tests := []*document.DocTestFile{
+		{
+			File:     path.Join(dir, "pkg_mod_func_ex_test.mojo"),
+			Name:     "ex",
+			Function: "test_ex",
+			Member:   "pkg.mod.func",
+			Line:     3,
+			Path:     []string{"pkg", "mod", "func"},
+		},
+	}
+
+	args := document.Config{TestOutput: dir, TestCommand: "true"}
+	assert.Nil(t, runDocTests(&args, tests))
+
+	args.TestCommand = `f() { echo "*** Failure: '$1/pkg_mod_func_ex_test.mojo::test_ex()' ***"; return 1; }; f`
+	err := runDocTests(&args, tests)
+	assert.EqualError(t, err, "1 doctest(s) failed")
+
+	args.TestCommand = "false"
+	err = runDocTests(&args, tests)
+	assert.EqualError(t, err, "in test command: exit status 1")
+
+	origins := map[string]*document.DocTestFile{tests[0].File: tests[0]}
+	assert.Equal(t, "FAIL doctest 'ex' in pkg.mod.func, line 3 ("+tests[0].File+")",
+		failureMessage(testFailure{File: tests[0].File, Function: "test_ex"}, origins))
+	assert.Equal(t, "FAIL other_test.mojo::test_x",
+		failureMessage(testFailure{File: "other_test.mojo", Function: "test_x"}, origins))
+
+	tests[0].Raises = true
+	assert.Equal(t, "FAIL doctest 'ex' in pkg.mod.func, line 3 ("+tests[0].File+")\n"+
+		"     Only errors from the 'raises' blocks are expected. Errors in setup or teardown blocks fail the test, too.",
+		failureMessage(testFailure{File: tests[0].File, Function: "test_ex"}, origins))
+}
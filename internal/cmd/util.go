@@ -198,11 +198,11 @@ func commandError(commandType string, err error) error {
 	return fmt.Errorf("in script %s: %s\nTo skip pre- and post-processing scripts, use flag '--bare'", commandType, err)
 }
 
-// bindFlags binds flags to Viper, filtering out the `--watch` and `--config` flag.
+// bindFlags binds flags to Viper, filtering out the `--watch`, `--run` and `--config` flag.
 func bindFlags(v *viper.Viper, flags *pflag.FlagSet) error {
 	newFlags := pflag.NewFlagSet("root", pflag.ExitOnError)
 	flags.VisitAll(func(f *pflag.Flag) {
-		if f.Name == "watch" || f.Name == "config" {
+		if f.Name == "watch" || f.Name == "run" || f.Name == "config" {
 			return
 		}
 		newFlags.AddFlag(f)
@@ -0,0 +1,58 @@
+package document
+
+import (
+	"sort"
+	"strings"
+)
+
+// Records a cross ref from the member with the given original path to a placeholder.
+// References are recorded for the member that owns the target's page.
+func (proc *Processor) addReference(elems []string, placeholder string) {
+	source, ok := proc.linkExports[strings.Join(elems, ".")]
+	if !ok {
+		return
+	}
+	target, _, _ := strings.Cut(placeholder, " ")
+	target, _, _ = strings.Cut(target, "(")
+	if entry, ok := proc.linkTargets[target]; ok && entry.IsSection && entry.Kind == "member" {
+		target = target[:strings.LastIndex(target, ".")]
+	}
+	if target == source {
+		return
+	}
+	if _, ok := proc.references[target]; !ok {
+		proc.references[target] = map[string]bool{}
+	}
+	proc.references[target][source] = true
+}
+
+// Sets the referencing members of all structs, traits and functions, for rendering backlinks.
+// Runs on the re-structured package, before renaming.
+func (proc *Processor) collectBacklinks() {
+	pc := pathHelper{
+		AddPathFunc: func(elem Named, elPath, filePath []string, kind string, isSection bool) {
+			if isSection {
+				return
+			}
+			var refs *[]string
+			switch e := elem.(type) {
+			case *Struct:
+				refs = &e.ReferencedBy
+			case *Trait:
+				refs = &e.ReferencedBy
+			case *Function:
+				refs = &e.ReferencedBy
+			default:
+				return
+			}
+			sources := proc.references[strings.Join(elPath, ".")]
+			*refs = make([]string, 0, len(sources))
+			for s := range sources {
+				*refs = append(*refs, s)
+			}
+			sort.Strings(*refs)
+		},
+		SetLink: false,
+	}
+	pc.collectPathsPackage(proc.ExportDocs.Decl, []string{}, []string{})
+}
@@ -0,0 +1,56 @@
+package document
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBacklinks(t *testing.T) {
+	yml := `
+decl:
+  name: modo
+  kind: package
+  description: |
+    Exports:
+     - mod1.Struct1
+     - mod1.Trait1
+     - mod1.func
+  modules:
+    - name: mod1
+      kind: module
+      summary: See [.Struct1.method] and [.func].
+      structs:
+        - name: Struct1
+          kind: struct
+          summary: Implements [.Trait1], see also [.Struct1.method].
+          functions:
+            - name: method
+              kind: function
+              overloads:
+                - name: method
+                  kind: function
+                  summary: Calls [modo.mod1.func].
+      traits:
+        - name: Trait1
+          kind: trait
+      functions:
+        - name: func
+          kind: function
+          overloads:
+            - name: func
+              kind: function
+              summary: Uses [.Struct1].
+`
+	docs, err := FromYAML([]byte(yml))
+	assert.Nil(t, err)
+
+	files := renderFiles(t, docs, &Config{ShortLinks: true, UseExports: true, Strict: true})
+
+	// Refs from the module are not recorded, as it is not exported.
+	assert.True(t, strings.HasSuffix(files["modo/Struct1.md"], "## Referenced by\n\n- [`func`](func.md)\n\n"))
+	assert.True(t, strings.HasSuffix(files["modo/Trait1.md"], "## Referenced by\n\n- [`Struct1`](Struct1.md)\n\n"))
+	assert.True(t, strings.HasSuffix(files["modo/func.md"], "## Referenced by\n\n- [`Struct1`](Struct1.md)\n\n"))
+	assert.NotContains(t, files["modo/_index.md"], "Referenced by")
+}
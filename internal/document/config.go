@@ -10,26 +10,31 @@ import (
 
 // Config holds the configuration for the documentation processor.
 type Config struct {
-	InputFiles      []string          `mapstructure:"input" yaml:"input"`
-	Sources         []string          `mapstructure:"source" yaml:"source"`
-	SourceURLs      map[string]string `mapstructure:"source-url" yaml:"source-url"`
-	OutputDir       string            `mapstructure:"output" yaml:"output"`
-	TestOutput      string            `mapstructure:"tests" yaml:"tests"`
-	RenderFormat    string            `mapstructure:"format" yaml:"format"`
-	UseExports      bool              `mapstructure:"exports" yaml:"exports"`
-	ShortLinks      bool              `mapstructure:"short-links" yaml:"short-links"`
-	ReportMissing   bool              `mapstructure:"report-missing" yaml:"report-missing"`
-	Strict          bool              `mapstructure:"strict" yaml:"strict"`
-	DryRun          bool              `mapstructure:"dry-run" yaml:"dry-run"`
-	CaseInsensitive bool              `mapstructure:"case-insensitive" yaml:"case-insensitive"`
-	Bare            bool              `mapstructure:"bare" yaml:"bare"`
-	TemplateDirs    []string          `mapstructure:"templates" yaml:"templates"`
-	PreRun          []string          `mapstructure:"pre-run" yaml:"pre-run"`
-	PreBuild        []string          `mapstructure:"pre-build" yaml:"pre-build"`
-	PreTest         []string          `mapstructure:"pre-test" yaml:"pre-test"`
-	PostTest        []string          `mapstructure:"post-test" yaml:"post-test"`
-	PostBuild       []string          `mapstructure:"post-build" yaml:"post-build"`
-	PostRun         []string          `mapstructure:"post-run" yaml:"post-run"`
+	InputFiles       []string                   `mapstructure:"input" yaml:"input"`
+	Sources          []string                   `mapstructure:"source" yaml:"source"`
+	SourceURLs       map[string]string          `mapstructure:"source-url" yaml:"source-url"`
+	Inventories      map[string]InventoryConfig `mapstructure:"inventories" yaml:"inventories"`
+	OutputDir        string                     `mapstructure:"output" yaml:"output"`
+	TestOutput       string                     `mapstructure:"tests" yaml:"tests"`
+	TestCommand      string                     `mapstructure:"test-command" yaml:"test-command"`
+	RenderFormat     string                     `mapstructure:"format" yaml:"format"`
+	UseExports       bool                       `mapstructure:"exports" yaml:"exports"`
+	ShortLinks       bool                       `mapstructure:"short-links" yaml:"short-links"`
+	InheritedMethods bool                       `mapstructure:"inherited-methods" yaml:"inherited-methods"`
+	ReportMissing    bool                       `mapstructure:"report-missing" yaml:"report-missing"`
+	SearchIndex      bool                       `mapstructure:"search-index" yaml:"search-index"`
+	Inventory        bool                       `mapstructure:"inventory" yaml:"inventory"`
+	Strict           bool                       `mapstructure:"strict" yaml:"strict"`
+	DryRun           bool                       `mapstructure:"dry-run" yaml:"dry-run"`
+	CaseInsensitive  bool                       `mapstructure:"case-insensitive" yaml:"case-insensitive"`
+	Bare             bool                       `mapstructure:"bare" yaml:"bare"`
+	TemplateDirs     []string                   `mapstructure:"templates" yaml:"templates"`
+	PreRun           []string                   `mapstructure:"pre-run" yaml:"pre-run"`
+	PreBuild         []string                   `mapstructure:"pre-build" yaml:"pre-build"`
+	PreTest          []string                   `mapstructure:"pre-test" yaml:"pre-test"`
+	PostTest         []string                   `mapstructure:"post-test" yaml:"post-test"`
+	PostBuild        []string                   `mapstructure:"post-build" yaml:"post-build"`
+	PostRun          []string                   `mapstructure:"post-run" yaml:"post-run"`
 }
 
 // ConfigFromViper creates a new Config from a viper.Viper instance.
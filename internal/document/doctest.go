@@ -2,28 +2,69 @@ package document
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path"
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/mlange-42/modo/internal/util"
 )
 
+const docTestManifestFile = "doctests.json"
+
 const docTestAttr = "doctest"
 const hideAttr = "hide"
 const globalAttr = "global"
+const outputAttr = "output"
+const skipAttr = "skip"
+const raisesAttr = "raises"
+const compileOnlyAttr = "compile-only"
+
+// blockAttr holds the doctest attributes of a code block.
+type blockAttr struct {
+	Name        string
+	Hide        bool
+	Global      bool
+	Output      bool
+	Skip        bool
+	Raises      bool
+	RaisesMsg   string
+	CompileOnly bool
+}
 
 func (proc *Processor) extractDocTests() error {
 	proc.docTests = []*docTest{}
 	w := walker{
-		Func:     proc.extractTests,
-		NameFunc: func(elem Named) string { return elem.GetFileName() },
+		Func:            proc.extractTests,
+		NameFunc:        func(elem Named) string { return elem.GetFileName() },
+		DescriptionFunc: proc.extractTestsDescription,
 	}
 	return w.walkAllDocStrings(proc.Docs)
 }
 
-func (proc *Processor) extractDocTestsMarkdown(baseDir string, build bool) error {
+// Extracts tests from the description of a member.
+// Line numbers of the tests are shifted by the summary and the blank line after it,
+// to count from the start of the docstring.
+func (proc *Processor) extractTestsDescription(summary, text string, elems []string, modElems int) (string, error) {
+	numTests := len(proc.docTests)
+	text, err := proc.extractTests(text, elems, modElems)
+	if err != nil {
+		return "", err
+	}
+	offset := 0
+	if summary = strings.TrimRight(summary, "\n"); summary != "" {
+		offset = strings.Count(summary, "\n") + 2
+	}
+	for _, test := range proc.docTests[numTests:] {
+		test.Line += offset
+	}
+	return text, nil
+}
+
+func (proc *Processor) extractDocTestsMarkdown(baseDir string, build bool, procs []*Processor) error {
 	proc.docTests = []*docTest{}
 	outDir := filepath.Clean(proc.Config.OutputDir)
 	baseDir = filepath.Clean(baseDir)
@@ -35,7 +76,7 @@ func (proc *Processor) extractDocTestsMarkdown(baseDir string, build bool) error
 			if info.IsDir() {
 				return nil
 			}
-			return proc.extractMarkdown(p, baseDir, outDir, build)
+			return proc.extractMarkdown(p, baseDir, outDir, build, procs)
 		})
 	if err != nil {
 		return err
@@ -50,7 +91,7 @@ func (proc *Processor) extractDocTestsMarkdown(baseDir string, build bool) error
 	return nil
 }
 
-func (proc *Processor) extractMarkdown(file, baseDir, outDir string, build bool) error {
+func (proc *Processor) extractMarkdown(file, baseDir, outDir string, build bool, procs []*Processor) error {
 	if strings.HasSuffix(strings.ToLower(file), ".json") {
 		return nil
 	}
@@ -67,10 +108,27 @@ func (proc *Processor) extractMarkdown(file, baseDir, outDir string, build bool)
 	contentStr := string(content)
 	if strings.HasSuffix(strings.ToLower(file), ".md") {
 		var err error
+		numTests := len(proc.docTests)
 		contentStr, err = proc.extractTests(contentStr, []string{strings.TrimSuffix(relPath, ".md")}, 1)
 		if err != nil {
 			return err
 		}
+		for _, test := range proc.docTests[numTests:] {
+			test.Markdown = filepath.ToSlash(cleanPath)
+		}
+		pageDir := proc.relativeDir([]string{targetDir})
+		if build && len(procs) > 0 {
+			contentStr, err = proc.processPage(contentStr, pageDir, procs)
+			if err != nil {
+				return err
+			}
+		}
+		if pc, ok := proc.Formatter.(PageConverter); ok && build {
+			contentStr, targetPath, err = pc.ConvertPage(contentStr, targetPath, pageDir, proc, procs)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	if build {
@@ -88,29 +146,295 @@ func (proc *Processor) writeDocTests(dir string) error {
 		return nil
 	}
 	for _, test := range proc.docTests {
-		b := strings.Builder{}
-		err := proc.Template.ExecuteTemplate(&b, "doctest.mojo", test)
-		if err != nil {
-			return err
+		if test.Skip {
+			continue
 		}
-		filePath := strings.Join(test.Path, "_")
-		filePath += "_" + test.Name + "_test.mojo"
-		fullPath := path.Join(dir, filePath)
+		basePath := path.Join(dir, strings.Join(test.Path, "_")+"_"+test.Name)
+		fullPath := basePath + "_test.mojo"
 
 		parentDir, _ := filepath.Split(filepath.Clean(fullPath))
-		err = proc.mkDirs(parentDir)
+		err := proc.mkDirs(parentDir)
 		if err != nil {
 			return err
 		}
 
-		err = proc.writeFile(fullPath, b.String())
+		file := test.file(fullPath)
+		if len(test.Output) > 0 {
+			// Tests with expected output run the code as a separate program.
+			mainPath := basePath + "_main.mojo"
+			file.Main = mainPath
+			if test.MainFile, err = workingDirPath(mainPath); err != nil {
+				return err
+			}
+			if test.RunCommand, err = proc.docTestRunCommand(test.MainFile); err != nil {
+				return err
+			}
+			if err := proc.writeDocTestFile(mainPath, "doctest_main.mojo", test); err != nil {
+				return err
+			}
+		}
+		if err := proc.writeDocTestFile(fullPath, "doctest.mojo", test); err != nil {
+			return err
+		}
+		proc.testFiles = append(proc.testFiles, file)
+	}
+	return nil
+}
+
+// WriteDocTestManifest writes the manifest of the given doctest files to the tests output directory.
+// File paths in the manifest are relative to that directory.
+// Test files listed in a previous manifest that were not written again are removed.
+func WriteDocTestManifest(config *Config, files []*DocTestFile) error {
+	if config.TestOutput == "" || config.DryRun {
+		return nil
+	}
+	entries := make([]DocTestFile, 0, len(files))
+	written := map[string]bool{}
+	for _, f := range files {
+		entry := *f
+		entry.File = manifestPath(config.TestOutput, f.File)
+		if f.Main != "" {
+			entry.Main = manifestPath(config.TestOutput, f.Main)
+		}
+		entries = append(entries, entry)
+		written[entry.File] = true
+		written[entry.Main] = true
+	}
+
+	previous, err := readDocTestManifest(config.TestOutput)
+	if err != nil {
+		return err
+	}
+	if err := removeDocTestFiles(config.TestOutput, previous, written); err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.TestOutput, os.ModePerm); err != nil && !os.IsExist(err) {
+		return err
+	}
+	return os.WriteFile(path.Join(config.TestOutput, docTestManifestFile), append(content, '\n'), 0644)
+}
+
+// CleanDocTests removes the doctest files listed in the manifest of the given tests directory,
+// as well as the manifest itself. Other files in the directory are retained.
+// Without a manifest, e.g. for tests generated by earlier versions, files named like doctests are removed.
+func CleanDocTests(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	exists, _, err := util.FileExists(path.Join(dir, docTestManifestFile))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return cleanDocTestsWithoutManifest(dir)
+	}
+	tests, err := readDocTestManifest(dir)
+	if err != nil {
+		return err
+	}
+	if err := removeDocTestFiles(dir, tests, nil); err != nil {
+		return err
+	}
+	if err := os.Remove(path.Join(dir, docTestManifestFile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Removes files named like generated doctests from a tests directory without manifest.
+func cleanDocTestsWithoutManifest(dir string) error {
+	tests := []DocTestFile{}
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
 		if err != nil {
+			if os.IsNotExist(err) && p == dir {
+				return filepath.SkipDir
+			}
 			return err
 		}
+		if d.IsDir() || !strings.HasSuffix(p, "_test.mojo") && !strings.HasSuffix(p, "_main.mojo") {
+			return nil
+		}
+		tests = append(tests, DocTestFile{File: manifestPath(dir, p)})
+		return nil
+	})
+	if err != nil || len(tests) == 0 {
+		return err
+	}
+	fmt.Printf("WARNING: no doctest manifest found in %s. Removing %d file(s) named like doctests.\n", dir, len(tests))
+	return removeDocTestFiles(dir, tests, nil)
+}
+
+// Reads the doctest manifest from the given tests directory.
+// Returns no entries if there is no manifest.
+func readDocTestManifest(dir string) ([]DocTestFile, error) {
+	file := path.Join(dir, docTestManifestFile)
+	content, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	tests := []DocTestFile{}
+	if err := json.Unmarshal(content, &tests); err != nil {
+		return nil, fmt.Errorf("error parsing doctest manifest %s: %s", file, err.Error())
+	}
+	return tests, nil
+}
+
+// Removes the files of the given manifest entries, except those to keep.
+// Directories left empty are removed as well, up to the tests directory.
+// Paths pointing outside the tests directory are ignored.
+func removeDocTestFiles(dir string, tests []DocTestFile, keep map[string]bool) error {
+	for _, t := range tests {
+		for _, f := range []string{t.File, t.Main} {
+			if f == "" || keep[f] || !filepath.IsLocal(filepath.FromSlash(f)) {
+				continue
+			}
+			file := filepath.Join(dir, filepath.FromSlash(f))
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			removeEmptyDirs(dir, filepath.Dir(filepath.FromSlash(f)))
+		}
 	}
 	return nil
 }
 
+// Removes the given sub-directory of root and its parents while they are empty.
+func removeEmptyDirs(root, dir string) {
+	for ; dir != "." && filepath.IsLocal(dir); dir = filepath.Dir(dir) {
+		if err := os.Remove(filepath.Join(root, dir)); err != nil {
+			return
+		}
+	}
+}
+
+func manifestPath(dir, file string) string {
+	rel, err := filepath.Rel(dir, file)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func (proc *Processor) writeDocTestFile(file, templ string, test *docTest) error {
+	b := strings.Builder{}
+	if err := proc.Template.ExecuteTemplate(&b, templ, test); err != nil {
+		return err
+	}
+	return proc.writeFile(file, b.String())
+}
+
+// Creates the command for running the main file of a doctest with expected output.
+// The main file must be given relative to the working directory.
+// The parent directories of the configured sources are used as include paths.
+// Paths are quoted, and relative to the working directory, which is the project root when running 'modo test'.
+// Thus, tests must be run from there, but are independent of the project's location.
+// The generated test checks for the main file, to fail with a helpful message otherwise.
+func (proc *Processor) docTestRunCommand(mainFile string) (string, error) {
+	parts := []string{"mojo", "run"}
+	includes := map[string]bool{}
+	for _, src := range proc.Config.Sources {
+		dir, err := workingDirPath(filepath.Dir(filepath.Clean(src)))
+		if err != nil {
+			return "", err
+		}
+		if includes[dir] {
+			continue
+		}
+		includes[dir] = true
+		parts = append(parts, "-I", util.ShellQuote(dir))
+	}
+	return strings.Join(append(parts, util.ShellQuote(mainFile)), " "), nil
+}
+
+// Converts a path to a slash-separated path relative to the working directory.
+// Falls back to the absolute path if there is no relative path, like for different drives on Windows.
+func workingDirPath(p string) (string, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(cwd, abs)
+	if err != nil {
+		return filepath.ToSlash(abs), nil
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// Creates the manifest entry of the test, written to the given file.
+func (t *docTest) file(file string) *DocTestFile {
+	f := DocTestFile{File: file, Name: t.Name, Path: t.Path, Markdown: t.Markdown, Line: t.Line, Raises: t.Raises}
+	if len(t.Code) > 0 || len(t.Output) > 0 {
+		f.Function = "test_" + t.Name
+	}
+	if t.Markdown == "" {
+		f.Member = strings.Join(t.Path, ".")
+	}
+	return &f
+}
+
+// Checks that the test doesn't combine mutually exclusive modes.
+func (t *docTest) checkModes() error {
+	modes := []string{}
+	if len(t.Output) > 0 {
+		modes = append(modes, outputAttr)
+	}
+	if t.Raises {
+		modes = append(modes, raisesAttr)
+	}
+	if t.CompileOnly {
+		modes = append(modes, compileOnlyAttr)
+	}
+	if len(modes) > 1 {
+		return fmt.Errorf("doctest '%s' combines exclusive attributes '%s'", t.Name, strings.Join(modes, "', '"))
+	}
+	return nil
+}
+
+// SetupCode returns the code lines before the code that is expected to raise.
+func (t *docTest) SetupCode() []string {
+	if t.RaisesTo == 0 {
+		return nil
+	}
+	return t.Code[:t.RaisesFrom]
+}
+
+// RaisingCode returns the code lines that are expected to raise.
+// These are the lines from the first to the last code block with attribute 'raises',
+// or all code if the attribute was set on other blocks only.
+func (t *docTest) RaisingCode() []string {
+	if t.RaisesTo == 0 {
+		return t.Code
+	}
+	return t.Code[t.RaisesFrom:t.RaisesTo]
+}
+
+// TeardownCode returns the code lines after the code that is expected to raise.
+// They are run in a finally branch, and can only use variables declared in the setup code.
+func (t *docTest) TeardownCode() []string {
+	if t.RaisesTo == 0 {
+		return nil
+	}
+	return t.Code[t.RaisesTo:]
+}
+
+// ExpectedOutput returns the expected output of the test, as a single string.
+// Leading and trailing whitespace is removed.
+func (t *docTest) ExpectedOutput() string {
+	return strings.TrimSpace(strings.Join(t.Output, "\n"))
+}
+
 func (proc *Processor) extractTests(text string, elems []string, modElems int) (string, error) {
 	t, tests, err := extractTestsText(text, elems, proc.Config.Strict)
 	if err != nil {
@@ -128,9 +452,9 @@ func extractTestsText(text string, elems []string, strict bool) (string, []*docT
 	blocks := map[string]*docTest{}
 	var blockLines []string
 	var globalLines []string
-	var blockName string
-	var excluded bool
-	var global bool
+	var outputLines []string
+	var attr blockAttr
+	var blockLine int
 	var count int
 	for scanner.Scan() {
 		origLine := scanner.Text()
@@ -140,26 +464,29 @@ func extractTestsText(text string, elems []string, strict bool) (string, []*docT
 		if currFence != fenceNone && fenced == fenceNone {
 			var ok bool
 			var err error
-			blockName, excluded, global, ok, err = parseBlockAttr(origLine)
+			attr, ok, err = parseBlockAttr(origLine)
 			if err != nil {
 				if err := warnOrError(strict, "%s in %s", err.Error(), strings.Join(elems, ".")); err != nil {
 					return "", nil, err
 				}
 			}
 			if !ok {
-				blockName = ""
+				attr.Name = ""
 			}
 			fenced = currFence
 			isStart = true
+			blockLine = count + 1
 		}
 
-		if !excluded {
+		if !attr.Hide {
 			outText.WriteString(origLine)
 			outText.WriteRune('\n')
 		}
 
-		if fenced != fenceNone && currFence != fenced && blockName != "" {
-			if global {
+		if fenced != fenceNone && currFence != fenced && attr.Name != "" {
+			if attr.Output {
+				outputLines = append(outputLines, origLine)
+			} else if attr.Global {
 				globalLines = append(globalLines, origLine)
 			} else {
 				blockLines = append(blockLines, origLine)
@@ -168,27 +495,47 @@ func extractTestsText(text string, elems []string, strict bool) (string, []*docT
 		count++
 
 		if fenced != fenceNone && currFence == fenced && !isStart {
-			if blockName == "" {
-				excluded = false
-				global = false
+			if attr.Name == "" {
+				attr = blockAttr{}
 				fenced = fenceNone
 				continue
 			}
-			if dt, ok := blocks[blockName]; ok {
+			dt, ok := blocks[attr.Name]
+			codeStart := 0
+			if ok {
+				codeStart = len(dt.Code)
 				dt.Code = append(dt.Code, blockLines...)
 				dt.Global = append(dt.Global, globalLines...)
+				dt.Output = append(dt.Output, outputLines...)
 			} else {
-				blocks[blockName] = &docTest{
-					Name:   blockName,
+				dt = &docTest{
+					Name:   attr.Name,
 					Path:   elems,
 					Code:   append([]string{}, blockLines...),
 					Global: append([]string{}, globalLines...),
+					Output: append([]string(nil), outputLines...),
+					Line:   blockLine,
 				}
+				blocks[attr.Name] = dt
+			}
+			// Test modes set on any block apply to the entire test.
+			dt.Skip = dt.Skip || attr.Skip
+			dt.Raises = dt.Raises || attr.Raises
+			dt.CompileOnly = dt.CompileOnly || attr.CompileOnly
+			if attr.RaisesMsg != "" {
+				dt.RaisesMsg = attr.RaisesMsg
+			}
+			if attr.Raises && len(blockLines) > 0 {
+				// Only code blocks with the attribute are expected to raise.
+				if dt.RaisesTo == 0 {
+					dt.RaisesFrom = codeStart
+				}
+				dt.RaisesTo = len(dt.Code)
 			}
 			blockLines = blockLines[:0]
 			globalLines = globalLines[:0]
-			excluded = false
-			global = false
+			outputLines = outputLines[:0]
+			attr = blockAttr{}
 			fenced = fenceNone
 		}
 	}
@@ -203,6 +550,12 @@ func extractTestsText(text string, elems []string, strict bool) (string, []*docT
 
 	tests := make([]*docTest, 0, len(blocks))
 	for _, block := range blocks {
+		if err := block.checkModes(); err != nil {
+			if err := warnOrError(strict, "%s in %s", err.Error(), strings.Join(elems, ".")); err != nil {
+				return "", nil, err
+			}
+			continue
+		}
 		tests = append(tests, block)
 	}
 	sort.Slice(tests, func(i, j int) bool { return tests[i].Name < tests[j].Name })
@@ -210,7 +563,7 @@ func extractTestsText(text string, elems []string, strict bool) (string, []*docT
 	return strings.TrimSuffix(outText.String(), "\n"), tests, nil
 }
 
-func parseBlockAttr(line string) (name string, hide bool, global bool, ok bool, err error) {
+func parseBlockAttr(line string) (attr blockAttr, ok bool, err error) {
 	parts := strings.SplitN(line, "{", 2)
 	if len(parts) < 2 {
 		return
@@ -231,8 +584,8 @@ func parseBlockAttr(line string) (name string, hide bool, global bool, ok bool,
 	})
 
 	for _, pair := range attrPairs {
-		elems := strings.Split(pair, "=")
-		if len(elems) > 2 {
+		elems := strings.SplitN(pair, "=", 2)
+		if len(elems) == 2 && !strings.HasPrefix(elems[1], "\"") && strings.Contains(elems[1], "=") {
 			err = fmt.Errorf("malformed code block attributes '%s'", pair)
 			return
 		}
@@ -241,31 +594,55 @@ func parseBlockAttr(line string) (name string, hide bool, global bool, ok bool,
 		}
 
 		key := strings.TrimSpace(elems[0])
-		if key == docTestAttr {
-			name = strings.Trim(elems[1], "\"")
-			continue
-		}
-		if key == hideAttr {
-			h := strings.Trim(elems[1], "\" ")
-			if h == "true" {
-				hide = true
-			} else if h != "false" {
-				err = fmt.Errorf("invalid argument in code block attribute 'hide': '%s'", h)
+		switch key {
+		case docTestAttr:
+			attr.Name = strings.Trim(elems[1], "\"")
+		case hideAttr:
+			if attr.Hide, err = parseBoolAttr(key, strings.Trim(elems[1], "\" ")); err != nil {
 				return
 			}
-			continue
-		}
-		if key == globalAttr {
-			g := strings.Trim(elems[1], "\"")
-			if g == "true" {
-				global = true
-			} else if g != "false" {
-				err = fmt.Errorf("invalid argument in code block attribute 'global': '%s'", g)
+		case globalAttr:
+			if attr.Global, err = parseBoolAttr(key, strings.Trim(elems[1], "\"")); err != nil {
+				return
+			}
+		case outputAttr:
+			if attr.Output, err = parseBoolAttr(key, strings.Trim(elems[1], "\"")); err != nil {
+				return
+			}
+		case skipAttr:
+			if attr.Skip, err = parseBoolAttr(key, strings.Trim(elems[1], "\"")); err != nil {
+				return
+			}
+		case raisesAttr:
+			// A quoted value other than a boolean is the expected error message.
+			value := strings.Trim(elems[1], "\"")
+			if value != "true" && value != "false" && strings.HasPrefix(elems[1], "\"") && value != "" {
+				attr.Raises, attr.RaisesMsg = true, value
+				continue
+			}
+			if attr.Raises, err = parseBoolAttr(key, value); err != nil {
+				return
+			}
+		case compileOnlyAttr:
+			if attr.CompileOnly, err = parseBoolAttr(key, strings.Trim(elems[1], "\"")); err != nil {
 				return
 			}
-			continue
 		}
 	}
+	if attr.Global && attr.Output {
+		err = fmt.Errorf("code block attributes 'global' and 'output' can't be combined")
+		return
+	}
 	ok = true
 	return
 }
+
+func parseBoolAttr(key, value string) (bool, error) {
+	switch value {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid argument in code block attribute '%s': '%s'", key, value)
+}
@@ -32,13 +32,15 @@ func TestParseBlockAttributes(t *testing.T) {
 			"test", false, false, false, true},
 		{"```mojo {doctest=\"test\" global=true, hide=true}",
 			"test", false, false, false, true},
+		{"``` {doctest=\"test\" output=true global=true}",
+			"test", false, true, false, true},
 	}
 
 	for _, test := range tests {
-		name, hide, global, ok, err := parseBlockAttr(test.Text)
-		assert.Equal(t, name, test.Name, "Name %s", test.Text)
-		assert.Equal(t, hide, test.Hide, "Hide %s", test.Text)
-		assert.Equal(t, global, test.Global, "Global %s", test.Text)
+		attr, ok, err := parseBlockAttr(test.Text)
+		assert.Equal(t, attr.Name, test.Name, "Name %s", test.Text)
+		assert.Equal(t, attr.Hide, test.Hide, "Hide %s", test.Text)
+		assert.Equal(t, attr.Global, test.Global, "Global %s", test.Text)
 		assert.Equal(t, ok, test.Ok, "Ok %s", test.Text)
 		assert.Equal(t, err != nil, test.Error, "Err %s %s", test.Text, err)
 	}
@@ -85,9 +87,190 @@ func TestExtractDocTests(t *testing.T) {
 			"assert(b == 0)",
 		},
 		Global: []string{"struct Test:", "    pass"},
+		Line:   3,
 	})
 }
 
+func TestParseBlockAttributesModes(t *testing.T) {
+	attr, ok, err := parseBlockAttr("```mojo {doctest=\"test\" skip=true raises=true compile-only=true}")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, blockAttr{Name: "test", Skip: true, Raises: true, CompileOnly: true}, attr)
+
+	_, ok, err = parseBlockAttr("```mojo {doctest=\"test\" raises=yes}")
+	assert.False(t, ok)
+	assert.EqualError(t, err, "invalid argument in code block attribute 'raises': 'yes'")
+
+	attr, ok, err = parseBlockAttr("```mojo {doctest=\"test\" raises=\"index out of bounds: i=2\"}")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, blockAttr{Name: "test", Raises: true, RaisesMsg: "index out of bounds: i=2"}, attr)
+
+	_, ok, err = parseBlockAttr("```mojo {doctest=\"test\" raises=a=b}")
+	assert.False(t, ok)
+	assert.EqualError(t, err, "malformed code block attributes 'raises=a=b'")
+}
+
+func TestExtractDocTestsRaises(t *testing.T) {
+	text := "```mojo {doctest=\"test\" hide=true}\n" +
+		"var l = List[Int]()\n" +
+		"```\n" +
+		"```mojo {doctest=\"test\" raises=\"empty\"}\n" +
+		"_ = l.pop()\n" +
+		"```\n" +
+		"```mojo {doctest=\"test\" hide=true}\n" +
+		"print(l)\n" +
+		"```\n"
+
+	proc := NewProcessor(nil, nil, nil, &Config{Strict: true})
+	_, err := proc.extractTests(text, []string{"pkg", "f"}, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(proc.docTests))
+	test := proc.docTests[0]
+	assert.True(t, test.Raises)
+	assert.Equal(t, "empty", test.RaisesMsg)
+	assert.Equal(t, []string{"var l = List[Int]()"}, test.SetupCode())
+	assert.Equal(t, []string{"_ = l.pop()"}, test.RaisingCode())
+	assert.Equal(t, []string{"print(l)"}, test.TeardownCode())
+}
+
+func TestExtractDocTestsModes(t *testing.T) {
+	text := "```mojo {doctest=\"test1\" raises=true}\n" +
+		"raise Error()\n" +
+		"```\n" +
+		"```mojo {doctest=\"test2\" raises=true}\n" +
+		"raise Error()\n" +
+		"```\n" +
+		"```mojo {doctest=\"test2\" compile-only=true}\n" +
+		"raise Error()\n" +
+		"```\n"
+
+	proc := NewProcessor(nil, nil, nil, &Config{})
+	_, err := proc.extractTests(text, []string{"pkg", "f"}, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(proc.docTests))
+	assert.Equal(t, "test1", proc.docTests[0].Name)
+	assert.True(t, proc.docTests[0].Raises)
+
+	proc = NewProcessor(nil, nil, nil, &Config{Strict: true})
+	_, err = proc.extractTests(text, []string{"pkg", "f"}, 1)
+	assert.EqualError(t, err, "doctest 'test2' combines exclusive attributes 'raises', 'compile-only' in pkg.f")
+}
+
+func TestWriteDocTestsModes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{}
+	templ, err := LoadTemplates(&TestFormatter{}, "")
+	assert.Nil(t, err)
+	proc := NewProcessorWithWriter(nil, &TestFormatter{}, templ, &Config{}, func(file, text string) error {
+		files[file] = text
+		return nil
+	})
+	proc.docTests = []*docTest{
+		{Name: "skipped", Path: []string{"pkg"}, Code: []string{"a()"}, Skip: true},
+		{Name: "raises", Path: []string{"pkg"}, Code: []string{"a()"}, Raises: true},
+		{Name: "compile", Path: []string{"pkg"}, Code: []string{"a()"}, CompileOnly: true},
+		{Name: "message", Path: []string{"pkg"}, Code: []string{"var l = List[Int]()", "_ = l.pop()", "print(l)"},
+			Raises: true, RaisesMsg: "empty", RaisesFrom: 1, RaisesTo: 2},
+	}
+	assert.Nil(t, proc.writeDocTests(dir))
+
+	assert.Equal(t, 3, len(files))
+	assert.Equal(t, "\n\nfn test_raises() raises:\n"+
+		"    var doctest_raised = False\n"+
+		"    try:\n"+
+		"        a()\n"+
+		"    except e:\n"+
+		"        doctest_raised = True\n"+
+		"    if not doctest_raised:\n"+
+		"        raise Error(\"doctest 'raises' did not raise\")\n",
+		files[path.Join(dir, "pkg_raises_test.mojo")])
+	assert.Equal(t, "\n\nfn test_message() raises:\n"+
+		"    var l = List[Int]()\n"+
+		"    var doctest_raised = False\n"+
+		"    try:\n"+
+		"        _ = l.pop()\n"+
+		"    except e:\n"+
+		"        doctest_raised = True\n"+
+		"        if String(e).find(\"empty\") < 0:\n"+
+		"            raise Error(\"doctest 'message' raised an unexpected error: \" + String(e))\n"+
+		"    finally:\n"+
+		"        print(l)\n"+
+		"    if not doctest_raised:\n"+
+		"        raise Error(\"doctest 'message' did not raise\")\n",
+		files[path.Join(dir, "pkg_message_test.mojo")])
+	assert.Equal(t, "\n\nfn _doctest_compile() raises:\n"+
+		"    a()\n\n\n"+
+		"fn test_compile() raises:\n"+
+		"    # Compile-only: the code above is type-checked, but not executed.\n"+
+		"    pass\n",
+		files[path.Join(dir, "pkg_compile_test.mojo")])
+}
+
+func TestExtractDocTestsOutput(t *testing.T) {
+	text := "Docstring\n" +
+		"\n" +
+		"```mojo {doctest=\"test\" global=true hide=true}\n" +
+		"from pkg import f\n" +
+		"```\n" +
+		"\n" +
+		"```mojo {doctest=\"test\"}\n" +
+		"print(f(1))\n" +
+		"```\n" +
+		"\n" +
+		"```text {doctest=\"test\" output=true}\n" +
+		"2\n" +
+		"```\n"
+
+	proc := NewProcessor(nil, nil, nil, &Config{})
+	outText, err := proc.extractTests(text, []string{"pkg", "f"}, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, len(strings.Split(outText, "\n")))
+
+	assert.Equal(t, []*docTest{{
+		Name:   "test",
+		Path:   []string{"pkg", "f"},
+		Code:   []string{"print(f(1))"},
+		Global: []string{"from pkg import f"},
+		Output: []string{"2"},
+		Line:   3,
+	}}, proc.docTests)
+}
+
+func TestWriteDocTestsOutput(t *testing.T) {
+	t.Chdir(t.TempDir())
+	dir := "test"
+	files := map[string]string{}
+	templ, err := LoadTemplates(&TestFormatter{}, "")
+	assert.Nil(t, err)
+	config := Config{Sources: []string{"my src/pkg"}}
+	proc := NewProcessorWithWriter(nil, &TestFormatter{}, templ, &config, func(file, text string) error {
+		files[file] = text
+		return nil
+	})
+	proc.docTests = []*docTest{{
+		Name:   "test",
+		Path:   []string{"pkg", "f"},
+		Code:   []string{"print(f(1))"},
+		Global: []string{"from pkg import f"},
+		Output: []string{"\"2\"", ""},
+	}}
+	assert.Nil(t, proc.writeDocTests(dir))
+
+	mainFile := path.Join(dir, "pkg_f_test_main.mojo")
+	assert.Equal(t, "from pkg import f\n\n\ndef main():\n    print(f(1))\n", files[mainFile])
+	assert.Equal(t, "from os.path import exists\n"+
+		"from subprocess import run\n"+
+		"from testing import assert_equal\n\n\n"+
+		"fn test_test() raises:\n"+
+		"    # Paths are relative to the project root, so the test must be run from there.\n"+
+		"    if not exists(\"test/pkg_f_test_main.mojo\"):\n"+
+		"        raise Error(\"doctest 'test': main file \" + \"test/pkg_f_test_main.mojo\" + \" not found. Run the tests from the project root.\")\n"+
+		"    var output = run(\"mojo run -I 'my src' 'test/pkg_f_test_main.mojo'\")\n"+
+		"    assert_equal(String(output.strip()), String(\"\\\"2\\\"\"))\n",
+		files[path.Join(dir, "pkg_f_test_test.mojo")])
+}
+
 func TestExtractDocTests4Ticks(t *testing.T) {
 	text := "Docstring\n" +
 		"\n" +
@@ -115,6 +298,7 @@ func TestExtractDocTests4Ticks(t *testing.T) {
 		Path:   []string{"pkg", "Struct"},
 		Code:   []string{},
 		Global: []string{"````mojo {doctest=\"test2\"}", "Test1", "````"},
+		Line:   3,
 	}, proc.docTests[0])
 
 	assert.Equal(t, &docTest{
@@ -122,6 +306,7 @@ func TestExtractDocTests4Ticks(t *testing.T) {
 		Path:   []string{"pkg", "Struct"},
 		Code:   []string{},
 		Global: []string{"```mojo {doctest=\"test4\"}", "Test2", "```"},
+		Line:   9,
 	}, proc.docTests[1])
 }
 
@@ -145,11 +330,168 @@ func TestExtractTestsMarkdown(t *testing.T) {
 	), 0666)
 	assert.Nil(t, err)
 
-	err = ExtractTestsMarkdown(&config, &TestFormatter{}, inDir, true)
+	files, err := ExtractTestsMarkdown(&config, &TestFormatter{}, inDir, true)
 	assert.Nil(t, err)
+	assert.Equal(t, []*DocTestFile{
+		{
+			File:     path.Join(testDir, "_index_test1_test.mojo"),
+			Name:     "test1",
+			Function: "test_test1",
+			Markdown: path.Join(inDir, "_index.md"),
+			Line:     1,
+			Path:     []string{"/_index"},
+		},
+	}, files)
 
 	_, err = os.Stat(path.Join(outDir, "_index.md"))
 	assert.Nil(t, err)
 	_, err = os.Stat(path.Join(testDir, "_index_test1_test.mojo"))
 	assert.Nil(t, err)
 }
+
+func TestWriteDocTestManifest(t *testing.T) {
+	testDir := t.TempDir()
+	config := Config{TestOutput: testDir}
+
+	files := []*DocTestFile{
+		{
+			File:     path.Join(testDir, "pkg_mod_func_ex_test.mojo"),
+			Main:     path.Join(testDir, "pkg_mod_func_ex_main.mojo"),
+			Name:     "ex",
+			Function: "test_ex",
+			Member:   "pkg.mod.func",
+			Line:     3,
+			Path:     []string{"pkg", "mod", "func"},
+		},
+		{
+			File:     path.Join(testDir, "guide", "usage_global_test.mojo"),
+			Name:     "global",
+			Markdown: "docs/guide/usage.md",
+			Line:     12,
+			Path:     []string{"guide", "usage"},
+		},
+	}
+	assert.Nil(t, WriteDocTestManifest(&config, files))
+
+	content, err := os.ReadFile(path.Join(testDir, docTestManifestFile))
+	assert.Nil(t, err)
+	assert.Equal(t, `[
+  {
+    "file": "pkg_mod_func_ex_test.mojo",
+    "main": "pkg_mod_func_ex_main.mojo",
+    "name": "ex",
+    "function": "test_ex",
+    "member": "pkg.mod.func",
+    "line": 3
+  },
+  {
+    "file": "guide/usage_global_test.mojo",
+    "name": "global",
+    "markdown": "docs/guide/usage.md",
+    "line": 12
+  }
+]
+`, string(content))
+	assert.Equal(t, path.Join(testDir, "pkg_mod_func_ex_test.mojo"), files[0].File)
+
+	config = Config{TestOutput: t.TempDir(), DryRun: true}
+	assert.Nil(t, WriteDocTestManifest(&config, files))
+	_, err = os.Stat(path.Join(config.TestOutput, docTestManifestFile))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWriteDocTestManifestStale(t *testing.T) {
+	testDir := t.TempDir()
+	config := Config{TestOutput: testDir}
+
+	assert.Nil(t, os.MkdirAll(path.Join(testDir, "guide"), os.ModePerm))
+	for _, f := range []string{"a_test.mojo", "b_test.mojo", "b_main.mojo", "guide/c_test.mojo", "other.mojo"} {
+		assert.Nil(t, os.WriteFile(path.Join(testDir, f), []byte{}, 0644))
+	}
+
+	assert.Nil(t, WriteDocTestManifest(&config, []*DocTestFile{
+		{File: path.Join(testDir, "a_test.mojo"), Name: "a"},
+		{File: path.Join(testDir, "b_test.mojo"), Main: path.Join(testDir, "b_main.mojo"), Name: "b"},
+		{File: path.Join(testDir, "guide", "c_test.mojo"), Name: "c"},
+	}))
+	assert.Nil(t, WriteDocTestManifest(&config, []*DocTestFile{
+		{File: path.Join(testDir, "b_test.mojo"), Name: "b"},
+	}))
+
+	files, err := os.ReadDir(testDir)
+	assert.Nil(t, err)
+	names := []string{}
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	assert.Equal(t, []string{"b_test.mojo", docTestManifestFile, "other.mojo"}, names)
+
+	assert.Nil(t, CleanDocTests(testDir))
+	files, err = os.ReadDir(testDir)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(files))
+	assert.Equal(t, "other.mojo", files[0].Name())
+
+	assert.Nil(t, CleanDocTests(testDir))
+
+	assert.Nil(t, os.WriteFile(path.Join(testDir, docTestManifestFile), []byte("{"), 0644))
+	assert.NotNil(t, CleanDocTests(testDir))
+}
+
+func TestCleanDocTestsWithoutManifest(t *testing.T) {
+	testDir := t.TempDir()
+
+	assert.Nil(t, os.MkdirAll(path.Join(testDir, "guide"), os.ModePerm))
+	for _, f := range []string{"a_test.mojo", "b_main.mojo", "guide/c_test.mojo", "other.mojo"} {
+		assert.Nil(t, os.WriteFile(path.Join(testDir, f), []byte{}, 0644))
+	}
+
+	assert.Nil(t, CleanDocTests(testDir))
+	files, err := os.ReadDir(testDir)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(files))
+	assert.Equal(t, "other.mojo", files[0].Name())
+
+	assert.Nil(t, CleanDocTests(testDir))
+	assert.Nil(t, CleanDocTests(path.Join(testDir, "missing")))
+}
+
+func TestExtractDocTestsLine(t *testing.T) {
+	yml := `
+decl:
+  name: pkg
+  kind: package
+  summary: Package summary
+  description: |
+    ` + "```mojo {doctest=\"pkgtest\"}" + `
+    a()
+    ` + "```" + `
+  modules:
+    - name: mod
+      kind: module
+      structs:
+        - name: Struct
+          kind: struct
+          summary: |
+            A summary
+            over two lines.
+          description: |
+            Some text.
+
+            ` + "```mojo {doctest=\"test\"}" + `
+            b()
+            ` + "```" + `
+`
+	docs, err := FromYAML([]byte(yml))
+	assert.Nil(t, err)
+
+	proc := NewProcessor(docs, nil, nil, &Config{Strict: true})
+	assert.Nil(t, proc.extractDocTests())
+	assert.Equal(t, 2, len(proc.docTests))
+
+	lines := map[string]int{}
+	for _, test := range proc.docTests {
+		lines[test.Name] = test.Line
+	}
+	assert.Equal(t, map[string]int{"pkgtest": 3, "test": 6}, lines)
+}
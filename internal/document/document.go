@@ -147,6 +147,8 @@ type Struct struct {
 	ParentTraits       []*ParentTrait `yaml:"-" json:"-"`                       // remove tag on next stable release of Mojo.
 	ParentTraitsHelper ParentTraits   `yaml:"parentTraits" json:"parentTraits"` // remove on next stable release of Mojo.
 	Signature          string
+	ReferencedBy       []string            `yaml:"-" json:"-"`
+	InheritedMethods   []*InheritedMethods `yaml:"-" json:"-"`
 	MemberLink         `yaml:"-" json:"-"`
 }
 
@@ -212,6 +214,8 @@ type Function struct {
 	Signature                string
 	Parameters               []*Parameter
 	HasDefaultImplementation bool
+	OverloadIndex            int      `yaml:"-" json:"-"` // 1-based index among multiple overloads, 0 otherwise.
+	ReferencedBy             []string `yaml:"-" json:"-"`
 	MemberLink               `yaml:"-" json:"-"`
 }
 
@@ -256,9 +260,10 @@ func (f *Function) checkMissing(path string, stats *missingStats) (missing []mis
 
 // Returns holds information on function return type and docs
 type Returns struct {
-	Type string
-	Doc  string
-	Path string
+	Type     string
+	Doc      string
+	Path     string
+	TypeLink string `yaml:"-" json:"-"`
 }
 
 // Field holds the document for a field.
@@ -287,6 +292,8 @@ type Trait struct {
 	ParentTraits       []*ParentTrait `yaml:"-" json:"-"`                       // remove tag on next stable release of Mojo.
 	ParentTraitsHelper ParentTraits   `yaml:"parentTraits" json:"parentTraits"` // remove on next stable release of Mojo.
 	Deprecated         string
+	ReferencedBy       []string `yaml:"-" json:"-"`
+	ImplementedBy      []string `yaml:"-" json:"-"`
 	MemberLink         `yaml:"-" json:"-"`
 }
 
@@ -314,8 +321,9 @@ func (o *Trait) UnmarshalJSON(data []byte) error {
 
 // ParentTrait holds name and path information for a parent trait.
 type ParentTrait struct {
-	Name string
-	Path string
+	Name     string
+	Path     string
+	TypeLink string `yaml:"-" json:"-"`
 }
 
 // ParentTraits is a temporal wrapper to handle different versions of parent traits in JSON.
@@ -373,6 +381,7 @@ type Arg struct {
 	Traits      []*TraitEntry
 	PassingKind string
 	Default     string
+	TypeLink    string `yaml:"-" json:"-"`
 }
 
 func (a *Arg) checkMissing(path string, stats *missingStats) (missing []missingDocs) {
@@ -400,6 +409,7 @@ type Parameter struct {
 	Path        string
 	PassingKind string
 	Default     string
+	TypeLink    string `yaml:"-" json:"-"`
 }
 
 func (p *Parameter) checkMissing(path string, stats *missingStats) (missing []missingDocs) {
@@ -6,7 +6,7 @@ import "text/template"
 type Formatter interface {
 	// Accepts checks whether the formatter can accept the given files or directories.
 	Accepts(files []string) error
-	// ToFilePath converts a member path to a full file path.
+	// ToFilePath converts a member path, relative to the package output directory, to a file path.
 	ToFilePath(path string, kind string) string
 	// ToLinkPath converts a member path to a format-specific link.
 	ToLinkPath(path string, kind string) string
@@ -31,3 +31,47 @@ type PackageSource struct {
 	Name string
 	Path []string
 }
+
+// PageProcessor is an optional interface for formats that need to post-process
+// the final content of a page, after all links were resolved.
+type PageProcessor interface {
+	// ProcessPage alters the final content of a page.
+	// Argument dir is the page's base directory for relative links, relative to the output directory.
+	ProcessPage(text string, dir string, proc *Processor) (string, error)
+}
+
+// URLFormatter is an optional interface for formats where the URLs of rendered pages
+// differ from the output file paths, e.g. for static site generators.
+// It is used for URLs in the search index and link inventory.
+// Without it, the file path is used as URL.
+type URLFormatter interface {
+	// ToURL converts a member path, relative to the output directory, to a relative URL.
+	ToURL(path string, kind string) string
+}
+
+// PageConverter is an optional interface for formats that convert handwritten Markdown pages
+// to a different file format, like HTML.
+type PageConverter interface {
+	// ConvertPage converts a handwritten Markdown page, after cross-refs were resolved.
+	// Argument file is the page's output path, and dir its base directory relative to the output directory.
+	// Argument procs are the processors of all packages, e.g. for navigation.
+	// Returns the converted content and the new output path.
+	ConvertPage(text string, file string, dir string, proc *Processor, procs []*Processor) (string, string, error)
+}
+
+// Slugger is an optional interface for formats that create heading anchors
+// differently from GitHub-flavoured Markdown.
+// It is used for anchors in the search index and link inventory, and for checking links.
+type Slugger interface {
+	// Slug converts a heading or anchor to the anchor created by the format.
+	Slug(heading string) string
+}
+
+// LinkProcessor is an optional interface for formats that need to post-process
+// resolved links in handwritten Markdown pages.
+// It is usually implemented together with [PageProcessor], by formats that only alter links.
+type LinkProcessor interface {
+	// ProcessLinks alters the resolved links in the given text.
+	// Argument dir is the page's base directory for relative links, relative to the output directory.
+	ProcessLinks(text string, dir string, proc *Processor) (string, error)
+}
@@ -0,0 +1,56 @@
+package document
+
+import (
+	"sort"
+	"strings"
+)
+
+// Records the parent traits of a struct, for listing implementing structs on trait pages.
+// Runs on the re-structured package, so re-exported structs are recorded under their new path.
+func (proc *Processor) addImplementor(s *Struct, elPath []string) {
+	structPath := strings.Join(elPath, ".")
+	for _, t := range s.ParentTraits {
+		if t.Path == "" {
+			continue
+		}
+		if _, ok := proc.implementors[t.Path]; !ok {
+			proc.implementors[t.Path] = map[string]bool{}
+		}
+		proc.implementors[t.Path][structPath] = true
+	}
+}
+
+// Sets the implementing structs of all traits, for rendering.
+// Runs on the re-structured package, before renaming.
+func (proc *Processor) collectImplementors() {
+	byTrait := map[string]map[string]bool{}
+	for oldPath, structs := range proc.implementors {
+		newPath, ok := proc.linkExports[oldPath]
+		if !ok {
+			continue
+		}
+		if _, ok := byTrait[newPath]; !ok {
+			byTrait[newPath] = map[string]bool{}
+		}
+		for s := range structs {
+			byTrait[newPath][s] = true
+		}
+	}
+
+	pc := pathHelper{
+		AddPathFunc: func(elem Named, elPath, filePath []string, kind string, isSection bool) {
+			t, ok := elem.(*Trait)
+			if !ok || isSection {
+				return
+			}
+			structs := byTrait[strings.Join(elPath, ".")]
+			t.ImplementedBy = make([]string, 0, len(structs))
+			for s := range structs {
+				t.ImplementedBy = append(t.ImplementedBy, s)
+			}
+			sort.Strings(t.ImplementedBy)
+		},
+		SetLink: false,
+	}
+	pc.collectPathsPackage(proc.ExportDocs.Decl, []string{}, []string{})
+}
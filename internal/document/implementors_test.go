@@ -0,0 +1,55 @@
+package document
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImplementors(t *testing.T) {
+	yml := `
+decl:
+  name: modo
+  kind: package
+  description: |
+    Exports:
+     - mod1.Struct1
+     - mod1.Trait1
+     - mod2.Trait2
+  modules:
+    - name: mod1
+      kind: module
+      structs:
+        - name: Struct1
+          kind: struct
+        - name: Struct2
+          kind: struct
+      traits:
+        - name: Trait1
+          kind: trait
+    - name: mod2
+      kind: module
+      traits:
+        - name: Trait2
+          kind: trait
+`
+	docs, err := FromYAML([]byte(yml))
+	assert.Nil(t, err)
+	structs := docs.Decl.Modules[0].Structs
+	structs[0].ParentTraits = []*ParentTrait{
+		{Name: "Trait1", Path: "modo.mod1.Trait1"},
+		{Name: "Trait2", Path: "modo.mod2.Trait2"},
+		{Name: "Sized", Path: "stdlib.Sized"},
+	}
+	structs[1].ParentTraits = []*ParentTrait{
+		{Name: "Trait1", Path: "modo.mod1.Trait1"},
+	}
+
+	files := renderFiles(t, docs, &Config{ShortLinks: true, UseExports: true, Strict: true})
+
+	// Struct2 is not listed, as it is not exported.
+	assert.True(t, strings.HasSuffix(files["modo/Trait1.md"], "## Implemented by\n\n- [`Struct1`](Struct1.md)\n\n"))
+	assert.True(t, strings.HasSuffix(files["modo/Trait2.md"], "## Implemented by\n\n- [`Struct1`](Struct1.md)\n\n"))
+	assert.NotContains(t, files["modo/Struct1.md"], "Implemented by")
+}
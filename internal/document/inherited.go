@@ -0,0 +1,99 @@
+package document
+
+import (
+	"fmt"
+)
+
+// InheritedMethods holds the default-implemented methods a struct inherits from a trait.
+type InheritedMethods struct {
+	Trait     string             // Name of the trait.
+	TraitLink string             // Placeholder for a link to the trait, or its name as code if it is not documented.
+	Methods   []*InheritedMethod // Inherited methods, in the order of the trait, with one entry per inherited overload.
+}
+
+// InheritedMethod holds an inherited trait method.
+type InheritedMethod struct {
+	Name    string
+	Summary string
+	Link    string // Placeholder for a link to the method in the trait, or its name as code if it is not documented.
+}
+
+// Collects the default-implemented methods of parent traits that structs don't override.
+// Runs on the original structure, after cross-refs were replaced by placeholders.
+func (proc *Processor) collectInheritedMethods() {
+	pc := pathHelper{
+		AddPathFunc: func(elem Named, elPath, filePath []string, kind string, isSection bool) {
+			if s, ok := elem.(*Struct); ok && !isSection {
+				s.InheritedMethods = proc.inheritedMethods(s)
+			}
+		},
+		SetLink: false,
+	}
+	pc.collectPathsPackage(proc.Docs.Decl, []string{}, []string{})
+}
+
+func (proc *Processor) inheritedMethods(s *Struct) []*InheritedMethods {
+	result := []*InheritedMethods{}
+	for _, parent := range s.ParentTraits {
+		elem, ok := proc.allPaths[parent.Path]
+		if !ok {
+			continue
+		}
+		trait, ok := elem.(*Trait)
+		if !ok {
+			continue
+		}
+		newTraitPath, traitExported := proc.linkExports[parent.Path]
+
+		methods := []*InheritedMethod{}
+		for _, f := range trait.Functions {
+			overloads := f.Overloads
+			if len(overloads) == 0 {
+				overloads = []*Function{f}
+			}
+			for i, o := range overloads {
+				if !o.HasDefaultImplementation || isOverridden(o, s) {
+					continue
+				}
+				method := InheritedMethod{Name: f.Name, Summary: o.Summary, Link: fmt.Sprintf("`%s`", f.Name)}
+				if traitExported {
+					target := newTraitPath + "." + f.Name
+					if len(overloads) > 1 {
+						target = overloadLink(target, i+1)
+					}
+					method.Link = fmt.Sprintf("[%s `%s`]", target, f.Name)
+				}
+				methods = append(methods, &method)
+			}
+		}
+		if len(methods) == 0 {
+			continue
+		}
+
+		inherited := InheritedMethods{Trait: trait.Name, TraitLink: fmt.Sprintf("`%s`", trait.Name), Methods: methods}
+		if traitExported {
+			inherited.TraitLink = fmt.Sprintf("[%s `%s`]", newTraitPath, trait.Name)
+		}
+		result = append(result, &inherited)
+	}
+	return result
+}
+
+// Checks whether a struct overrides a trait method, using the same matching as doc inheritance.
+func isOverridden(traitFunc *Function, s *Struct) bool {
+	for _, f := range s.Functions {
+		if f.Name != traitFunc.Name {
+			continue
+		}
+		if len(f.Overloads) == 0 {
+			return functionsMatch(f, traitFunc)
+		}
+		for _, o := range f.Overloads {
+			if functionsMatch(o, traitFunc) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
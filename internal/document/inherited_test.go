@@ -0,0 +1,94 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInheritedMethods(t *testing.T) {
+	yml := `
+decl:
+  name: modo
+  kind: package
+  modules:
+    - name: mod1
+      kind: module
+      structs:
+        - name: Struct1
+          kind: struct
+          functions:
+            - name: overridden
+              kind: function
+              overloads:
+                - name: overridden
+                  kind: function
+                  args:
+                    - name: self
+                      kind: arg
+                      type: Self
+      traits:
+        - name: Trait1
+          kind: trait
+          functions:
+            - name: inherited
+              kind: function
+              overloads:
+                - name: inherited
+                  kind: function
+                  summary: An inherited method.
+                  hasdefaultimplementation: true
+            - name: overridden
+              kind: function
+              overloads:
+                - name: overridden
+                  kind: function
+                  hasdefaultimplementation: true
+                  args:
+                    - name: self
+                      kind: arg
+                      type: _Self
+            - name: required
+              kind: function
+              overloads:
+                - name: required
+                  kind: function
+            - name: multi
+              kind: function
+              overloads:
+                - name: multi
+                  kind: function
+                  summary: First overload.
+                  hasdefaultimplementation: true
+                  args:
+                    - name: self
+                      kind: arg
+                      type: _Self
+                - name: multi
+                  kind: function
+                  summary: Second overload.
+                  hasdefaultimplementation: true
+                  args:
+                    - name: self
+                      kind: arg
+                      type: _Self
+                    - name: x
+                      kind: arg
+                      type: Int
+`
+	docs, err := FromYAML([]byte(yml))
+	assert.Nil(t, err)
+	docs.Decl.Modules[0].Structs[0].ParentTraits = []*ParentTrait{
+		{Name: "Trait1", Path: "modo.mod1.Trait1"},
+		{Name: "Sized", Path: "stdlib.Sized"},
+	}
+
+	files := renderFiles(t, docs, &Config{ShortLinks: true, InheritedMethods: true, Strict: true})
+
+	assert.Contains(t, files["modo/mod1/Struct1.md"],
+		"## Inherited from [`Trait1`](Trait1.md)\n\n- [`inherited`](Trait1.md#inherited): An inherited method.\n"+
+			"- [`multi`](Trait1.md#multi-1): First overload.\n"+
+			"- [`multi`](Trait1.md#multi-2): Second overload.\n\n")
+	assert.NotContains(t, files["modo/mod1/Struct1.md"], "(Trait1.md#overridden)")
+	assert.NotContains(t, files["modo/mod1/Struct1.md"], "(Trait1.md#required)")
+}
@@ -0,0 +1,197 @@
+package document
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const inventoryFile = "objects.json"
+const inventoryVersion = 1
+
+// InventoryConfig configures a link inventory of another project, for external cross-refs.
+type InventoryConfig struct {
+	URL       string `mapstructure:"url" yaml:"url"`
+	Inventory string `mapstructure:"inventory" yaml:"inventory"`
+}
+
+// inventory is a lookup of link targets of a project, as written to objects.json.
+type inventory struct {
+	Version int                        `json:"version"`
+	Objects map[string]inventoryObject `json:"objects"`
+}
+
+// inventoryObject is a link target in an inventory.
+type inventoryObject struct {
+	Kind string `json:"kind"`
+	URL  string `json:"url"`
+}
+
+// Replaces a cross-ref by a Markdown link to another project, if it matches a configured inventory.
+// References to members of the processed package are never resolved externally.
+func (proc *Processor) refToExternalLink(link string) (string, bool, error) {
+	if strings.HasPrefix(link, ".") || len(proc.Config.Inventories) == 0 {
+		return "", false, nil
+	}
+	target, selector, customText := splitRef(link)
+	if _, ok := proc.linkExports[target]; ok {
+		return "", false, nil
+	}
+	target += selector
+
+	prefix, config, ok := proc.findInventory(target)
+	if !ok {
+		return "", false, nil
+	}
+	inv, err := proc.loadInventory(prefix, config)
+	if err != nil {
+		return "", false, err
+	}
+	obj, ok := inv.Objects[target]
+	if !ok {
+		err := proc.warnOrError("Can't resolve cross ref '%s' in inventory for '%s'", target, prefix)
+		return "", false, err
+	}
+
+	text := customText
+	if text == "" {
+		text = target
+		if proc.Config.ShortLinks {
+			textParts := strings.Split(target, ".")
+			if inv.isMember(target, &obj) && len(textParts) > 1 {
+				text = strings.Join(textParts[len(textParts)-2:], ".")
+			} else {
+				text = textParts[len(textParts)-1]
+			}
+		}
+		text = fmt.Sprintf("`%s`", text)
+	}
+	return fmt.Sprintf("[%s](%s)", text, inventoryURL(config, &obj)), true, nil
+}
+
+// Checks whether an inventory object is a member of a struct or trait.
+// Inventories written by earlier versions use kind "member" for these.
+func (inv *inventory) isMember(target string, obj *inventoryObject) bool {
+	if obj.Kind == "member" {
+		return true
+	}
+	idx := strings.LastIndex(target, ".")
+	if idx < 0 {
+		return false
+	}
+	parent, ok := inv.Objects[target[:idx]]
+	return ok && (parent.Kind == "struct" || parent.Kind == "trait")
+}
+
+// Returns the absolute URL of an inventory object.
+func inventoryURL(config *InventoryConfig, obj *inventoryObject) string {
+	return strings.TrimSuffix(config.URL, "/") + "/" + strings.TrimPrefix(obj.URL, "/")
+}
+
+// Finds the inventory with the longest package prefix matching the given path.
+func (proc *Processor) findInventory(target string) (string, *InventoryConfig, bool) {
+	lower := strings.ToLower(target)
+	best := ""
+	for prefix := range proc.Config.Inventories {
+		p := strings.ToLower(prefix)
+		if (lower == p || strings.HasPrefix(lower, p+".")) && len(p) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return "", nil, false
+	}
+	config := proc.Config.Inventories[best]
+	return best, &config, true
+}
+
+// Loads the inventory for a package prefix, from a local file or from a URL.
+// Inventories are cached for subsequent lookups.
+func (proc *Processor) loadInventory(prefix string, config *InventoryConfig) (*inventory, error) {
+	if inv, ok := proc.inventories[prefix]; ok {
+		return inv, nil
+	}
+	file := config.Inventory
+	if file == "" {
+		file = strings.TrimSuffix(config.URL, "/") + "/" + inventoryFile
+	}
+
+	var data []byte
+	var err error
+	if strings.HasPrefix(file, "http://") || strings.HasPrefix(file, "https://") {
+		data, err = fetch(file)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading inventory for '%s': %s", prefix, err.Error())
+	}
+
+	inv := inventory{}
+	if err := json.Unmarshal(data, &inv); err != nil {
+		return nil, fmt.Errorf("error parsing inventory %s: %s", file, err.Error())
+	}
+	if inv.Version != inventoryVersion {
+		return nil, fmt.Errorf("unsupported version %d of inventory %s", inv.Version, file)
+	}
+	proc.inventories[prefix] = &inv
+	return &inv, nil
+}
+
+// Writes the inventory of all link targets to the output directory.
+// Objects of other packages already present in the file are retained.
+func (proc *Processor) writeInventory() error {
+	inv := inventory{Version: inventoryVersion, Objects: map[string]inventoryObject{}}
+	baseDir := proc.relativeDir([]string{path.Join(proc.Config.OutputDir, proc.subdir)})
+	for link, target := range proc.linkTargets {
+		target.Elements = append([]string{}, target.Elements...)
+		link = proc.renameInLink(link, &target)
+		inv.Objects[link] = inventoryObject{
+			Kind: target.ElemKind,
+			URL:  proc.targetURL(baseDir, target.Elements, target.Kind, target.IsSection),
+		}
+	}
+	if proc.Config.DryRun {
+		return nil
+	}
+
+	file := path.Join(proc.Config.OutputDir, inventoryFile)
+	root := proc.ExportDocs.Decl.GetName()
+	if content, err := os.ReadFile(file); err == nil {
+		existing := inventory{}
+		if err := json.Unmarshal(content, &existing); err != nil {
+			return fmt.Errorf("error parsing inventory %s: %s", file, err.Error())
+		}
+		for p, obj := range existing.Objects {
+			if p != root && !strings.HasPrefix(p, root+".") {
+				inv.Objects[p] = obj
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	content, err := json.MarshalIndent(&inv, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(content, '\n'), 0644)
+}
+
+func fetch(url string) ([]byte, error) {
+	client := http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request to %s failed with status %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,139 @@
+package document
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceRefsExternal(t *testing.T) {
+	dir := t.TempDir()
+	invFile := path.Join(dir, "objects.json")
+	assert.Nil(t, os.WriteFile(invFile, []byte(`{
+  "version": 1,
+  "objects": {
+    "otherpkg.mod.Foo": {"kind": "struct", "url": "otherpkg/mod/Foo.md"},
+    "otherpkg.mod.Foo.bar": {"kind": "member", "url": "otherpkg/mod/Foo.md#bar"},
+    "otherpkg.mod.Foo.baz": {"kind": "function", "url": "otherpkg/mod/Foo.md#baz"}
+  }
+}`), 0644))
+
+	text := "A [.Struct], an external [otherpkg.mod.Foo], a [otherpkg.mod.Foo.bar custom text], a [otherpkg.mod.Foo.baz], and [otherpkg.mod.Baz]."
+	lookup := map[string]string{
+		"stdlib.p.Struct": "stdlib.p.Struct",
+	}
+	elems := []string{"stdlib", "p", "Struct"}
+
+	proc := NewProcessor(nil, &TestFormatter{}, nil, &Config{
+		ShortLinks: true,
+		Inventories: map[string]InventoryConfig{
+			"otherpkg": {URL: "https://example.com/docs/", Inventory: invFile},
+		},
+	})
+	proc.linkExports = lookup
+	out, err := proc.replaceRefs(text, elems, 2)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "A [stdlib.p.Struct], an external [`Foo`](https://example.com/docs/otherpkg/mod/Foo.md), "+
+		"a [custom text](https://example.com/docs/otherpkg/mod/Foo.md#bar), "+
+		"a [`Foo.baz`](https://example.com/docs/otherpkg/mod/Foo.md#baz), and [otherpkg.mod.Baz].", out)
+
+	proc.Config.Strict = true
+	_, err = proc.replaceRefs(text, elems, 2)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Can't resolve cross ref 'otherpkg.mod.Baz' in inventory for 'otherpkg'", err.Error())
+}
+
+func TestLoadInventoryError(t *testing.T) {
+	dir := t.TempDir()
+	invFile := path.Join(dir, "objects.json")
+	assert.Nil(t, os.WriteFile(invFile, []byte(`{"version": 2, "objects": {}}`), 0644))
+
+	proc := NewProcessor(nil, &TestFormatter{}, nil, &Config{
+		Inventories: map[string]InventoryConfig{
+			"otherpkg": {URL: "https://example.com/docs/", Inventory: invFile},
+		},
+	})
+	proc.linkExports = map[string]string{}
+	_, err := proc.replaceRefs("A [otherpkg.Foo].", []string{"stdlib"}, 1)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unsupported version 2 of inventory")
+
+	proc.Config.Inventories["otherpkg"] = InventoryConfig{URL: "https://example.com/docs/", Inventory: path.Join(dir, "missing.json")}
+	_, err = proc.replaceRefs("A [otherpkg.Foo].", []string{"stdlib"}, 1)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error loading inventory for 'otherpkg'")
+}
+
+func TestWriteInventory(t *testing.T) {
+	yml := `
+decl:
+  name: modo
+  kind: package
+  description: |
+    Exports:
+     - mod1.Struct1 as Renamed
+     - mod1.func
+     - mod1.A
+  modules:
+    - name: mod1
+      kind: module
+      aliases:
+        - name: A
+          kind: alias
+      structs:
+        - name: Struct1
+          kind: struct
+          functions:
+            - name: f
+              kind: function
+              overloads:
+                - name: f
+                  kind: function
+      functions:
+        - name: func
+          kind: function
+          overloads:
+            - name: func
+              kind: function
+`
+	docs, err := FromYAML([]byte(yml))
+	assert.Nil(t, err)
+
+	outDir := t.TempDir()
+	existing := inventory{Version: inventoryVersion, Objects: map[string]inventoryObject{
+		"other":    {Kind: "package", URL: "other/_index.md"},
+		"modo.old": {Kind: "module", URL: "modo/old/_index.md"},
+	}}
+	content, err := json.Marshal(&existing)
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(path.Join(outDir, inventoryFile), content, 0644))
+
+	formatter := TestFormatter{}
+	templ, err := LoadTemplates(&formatter, "")
+	assert.Nil(t, err)
+	config := Config{OutputDir: outDir, Inventory: true, UseExports: true}
+	proc := NewProcessorWithWriter(docs, &formatter, templ, &config, func(file, text string) error {
+		return nil
+	})
+
+	err = renderWith(&config, proc, "sub")
+	assert.Nil(t, err)
+
+	content, err = os.ReadFile(path.Join(outDir, inventoryFile))
+	assert.Nil(t, err)
+	inv := inventory{}
+	assert.Nil(t, json.Unmarshal(content, &inv))
+
+	assert.Equal(t, inventory{Version: inventoryVersion, Objects: map[string]inventoryObject{
+		"modo":           {Kind: "package", URL: "sub/modo/_index.md"},
+		"modo.Renamed":   {Kind: "struct", URL: "sub/modo/Renamed.md"},
+		"modo.A":         {Kind: "alias", URL: "sub/modo/_index.md#aliases"},
+		"modo.Renamed.f": {Kind: "function", URL: "sub/modo/Renamed.md#f"},
+		"modo.func":      {Kind: "function", URL: "sub/modo/func.md"},
+		"other":          {Kind: "package", URL: "other/_index.md"},
+	}}, inv)
+}
@@ -7,7 +7,9 @@ import (
 	"strings"
 )
 
-const linkRegexString = `(?s)(?:(` + "```.*?```)|(`.*?`" + `))|(\[[^\[].*?\])`
+// Link text may contain code spans with square brackets, like in [pkg.List `List[Int]`],
+// and one level of nested brackets, like in [pkg.f(List[Int])].
+const linkRegexString = `(?s)(?:(` + "```.*?```)|(`.*?`" + `))|(\[(?:` + "`[^`]*`" + `|[^\[\]` + "`" + `])(?:` + "`[^`]*`" + `|\[[^\[\]]*\]|[^\]` + "`" + `])*\])`
 const transcludeRegexString = `(?s)(?:(` + "```.*?```)|(`.*?`" + `))|\[(\[.*?\])\]`
 
 var linkRegex *regexp.Regexp
@@ -45,6 +47,15 @@ func (proc *Processor) replaceRefs(text string, elems []string, modElems int) (s
 		start, end := indices[i], indices[i+1]
 		link := text[start+1 : end-1]
 
+		external, ok, err := proc.refToExternalLink(link)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			text = fmt.Sprintf("%s%s%s", text[:start], external, text[end:])
+			continue
+		}
+
 		content, ok, err := proc.refToPlaceholder(link, elems, modElems, true)
 		if err != nil {
 			return "", err
@@ -52,6 +63,7 @@ func (proc *Processor) replaceRefs(text string, elems []string, modElems int) (s
 		if !ok {
 			continue
 		}
+		proc.addReference(elems, content)
 		text = fmt.Sprintf("%s[%s]%s", text[:start], content, text[end:])
 	}
 	return text, nil
@@ -108,7 +120,7 @@ func (proc *Processor) placeholderToLink(link string, elems []string, modElems i
 	} else {
 		if shorten {
 			textParts := strings.Split(text, ".")
-			if entry.IsSection && entry.Kind != "package" && entry.Kind != "module" {
+			if entry.IsSection && entry.Kind == "member" {
 				text = strings.Join(textParts[len(textParts)-2:], ".")
 			} else {
 				text = textParts[len(textParts)-1]
@@ -190,15 +202,15 @@ func (proc *Processor) renameInLink(link string, elems *elemPath) string {
 }
 
 func (proc *Processor) refToPlaceholder(link string, elems []string, modElems int, redirect bool) (string, bool, error) {
-	linkParts := strings.SplitN(link, " ", 2)
+	target, selector, text := splitRef(link)
 
 	var placeholder string
 	var ok bool
 	var err error
 	if strings.HasPrefix(link, ".") {
-		placeholder, ok, err = proc.refToPlaceholderRel(linkParts[0], elems, modElems, redirect)
+		placeholder, ok, err = proc.refToPlaceholderRel(target, elems, modElems, redirect)
 	} else {
-		placeholder, ok, err = proc.refToPlaceholderAbs(linkParts[0], elems, redirect)
+		placeholder, ok, err = proc.refToPlaceholderAbs(target, elems, redirect)
 	}
 	if err != nil {
 		return "", false, err
@@ -207,8 +219,19 @@ func (proc *Processor) refToPlaceholder(link string, elems []string, modElems in
 		return "", false, nil
 	}
 
-	if len(linkParts) > 1 {
-		return fmt.Sprintf("%s %s", placeholder, linkParts[1]), true, nil
+	if selector != "" {
+		if redirect {
+			placeholder, ok, err = proc.selectOverload(placeholder, selector, link, elems)
+			if err != nil || !ok {
+				return "", false, err
+			}
+		} else {
+			placeholder += selector
+		}
+	}
+
+	if text != "" {
+		return fmt.Sprintf("%s %s", placeholder, text), true, nil
 	}
 	return placeholder, true, nil
 }
@@ -237,7 +260,7 @@ func (proc *Processor) refToPlaceholderRel(link string, elems []string, modElems
 
 	placeholder, ok := proc.linkExports[fullLink]
 	if !ok {
-		err := proc.warnOrError("Can't resolve cross ref (rel) '%s' (%s) in %s", link, fullLink, strings.Join(elems, "."))
+		err := proc.warnOrError("Can't resolve cross ref (rel) '%s' (%s) in %s%s", link, fullLink, strings.Join(elems, "."), proc.unresolvedRefHint(fullLink))
 		return "", false, err
 	}
 	return placeholder, true, nil
@@ -249,7 +272,7 @@ func (proc *Processor) refToPlaceholderAbs(link string, elems []string, redirect
 	}
 	placeholder, ok := proc.linkExports[link]
 	if !ok {
-		err := proc.warnOrError("Can't resolve cross ref (abs) '%s' in %s", link, strings.Join(elems, "."))
+		err := proc.warnOrError("Can't resolve cross ref (abs) '%s' in %s%s", link, strings.Join(elems, "."), proc.unresolvedRefHint(link))
 		return "", false, err
 	}
 	return placeholder, true, nil
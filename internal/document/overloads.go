@@ -0,0 +1,142 @@
+package document
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Adds link targets for the individual overloads of a function or method.
+// Overloads are addressed by their 1-based index, like 'pkg.mod.Struct.__init__(2)',
+// and link to anchors like '#__init__-2'.
+func (proc *Processor) addOverloadTargets(f *Function, elPath, filePath []string, kind string, isSection bool) {
+	link := strings.Join(elPath, ".")
+	if len(f.Overloads) == 0 {
+		proc.linkOverloads[link] = []*Function{f}
+		return
+	}
+	proc.linkOverloads[link] = f.Overloads
+	if len(f.Overloads) < 2 {
+		return
+	}
+
+	basePath := filePath
+	if isSection {
+		basePath = filePath[:len(filePath)-1]
+	}
+	for i, o := range f.Overloads {
+		o.OverloadIndex = i + 1
+		overloadPath := appendNew(basePath, "#"+overloadAnchor(f.Name, i+1))
+		proc.linkTargets[overloadLink(link, i+1)] = elemPath{Elements: overloadPath, Kind: kind, ElemKind: elemKind(f, kind), IsSection: true}
+	}
+}
+
+// Resolves an overload selector like '(2)' or '(Int, String)' for a placeholder of a function or method.
+// Returns the placeholder of the selected overload.
+// For functions without multiple overloads, the placeholder is returned unchanged if the selector matches.
+func (proc *Processor) selectOverload(placeholder, selector, link string, elems []string) (string, bool, error) {
+	overloads, ok := proc.linkOverloads[placeholder]
+	if !ok {
+		err := proc.warnOrError("Can't resolve overload in cross ref '%s' in %s. The target is not a function", link, strings.Join(elems, "."))
+		return "", false, err
+	}
+	index := selectOverloadIndex(overloads, selector)
+	if index < 1 {
+		err := proc.warnOrError("Can't resolve overload in cross ref '%s' in %s. The target has %d overload(s)", link, strings.Join(elems, "."), len(overloads))
+		return "", false, err
+	}
+	if len(overloads) < 2 {
+		return placeholder, true, nil
+	}
+	return overloadLink(placeholder, index), true, nil
+}
+
+// Finds the 1-based index of the overload matching a selector,
+// given either as index or as comma-separated argument types.
+// Returns 0 if no overload matches.
+func selectOverloadIndex(overloads []*Function, selector string) int {
+	inner := strings.TrimSpace(selector[1 : len(selector)-1])
+	if index, err := strconv.Atoi(inner); err == nil {
+		if index < 1 || index > len(overloads) {
+			return 0
+		}
+		return index
+	}
+
+	types := splitTypes(inner)
+	for i, o := range overloads {
+		args := []string{}
+		for _, a := range o.Args {
+			if a.Name == "self" {
+				continue
+			}
+			args = append(args, strings.Join(strings.Fields(a.Type), ""))
+		}
+		if len(args) != len(types) {
+			continue
+		}
+		match := true
+		for j := range args {
+			if args[j] != types[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Splits a cross ref into the target, an optional overload selector in parentheses, and an optional link text.
+func splitRef(link string) (target, selector, text string) {
+	space := strings.IndexRune(link, ' ')
+	open := strings.IndexRune(link, '(')
+	if open < 0 || (space >= 0 && space < open) {
+		if space < 0 {
+			return link, "", ""
+		}
+		return link[:space], "", strings.TrimSpace(link[space+1:])
+	}
+	close := strings.IndexRune(link[open:], ')')
+	if close < 0 {
+		return link, "", ""
+	}
+	close += open
+	return link[:open], link[open : close+1], strings.TrimSpace(link[close+1:])
+}
+
+// Splits comma-separated types at the top level, ignoring commas in square brackets.
+// Whitespace is removed from the types.
+func splitTypes(s string) []string {
+	types := []string{}
+	if strings.TrimSpace(s) == "" {
+		return types
+	}
+	depth := 0
+	start := 0
+	for i, r := range s {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				types = append(types, strings.Join(strings.Fields(s[start:i]), ""))
+				start = i + 1
+			}
+		}
+	}
+	return append(types, strings.Join(strings.Fields(s[start:]), ""))
+}
+
+func overloadLink(link string, index int) string {
+	return fmt.Sprintf("%s(%d)", link, index)
+}
+
+// Creates the anchor of an overload, matching the heading slug of "`name` (index)".
+func overloadAnchor(name string, index int) string {
+	return fmt.Sprintf("%s-%d", strings.ToLower(name), index)
+}
@@ -0,0 +1,124 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverloadRefs(t *testing.T) {
+	yml := `
+decl:
+  name: modo
+  kind: package
+  modules:
+    - name: mod1
+      kind: module
+      summary: See [.Struct1.__init__(2)], [.Struct1.__init__(Int, Dict[String, Int])], [.func(1) first func] and [.Struct1.single(1)].
+      structs:
+        - name: Struct1
+          kind: struct
+          functions:
+            - name: __init__
+              kind: function
+              overloads:
+                - name: __init__
+                  kind: function
+                  args:
+                    - name: self
+                      kind: arg
+                      type: Self
+                - name: __init__
+                  kind: function
+                  args:
+                    - name: self
+                      kind: arg
+                      type: Self
+                    - name: a
+                      kind: arg
+                      type: Int
+                - name: __init__
+                  kind: function
+                  args:
+                    - name: self
+                      kind: arg
+                      type: Self
+                    - name: a
+                      kind: arg
+                      type: Int
+                    - name: b
+                      kind: arg
+                      type: Dict[String, Int]
+            - name: single
+              kind: function
+              overloads:
+                - name: single
+                  kind: function
+      functions:
+        - name: func
+          kind: function
+          overloads:
+            - name: func
+              kind: function
+            - name: func
+              kind: function
+`
+	docs, err := FromYAML([]byte(yml))
+	assert.Nil(t, err)
+
+	files := renderFiles(t, docs, &Config{ShortLinks: true, Strict: true})
+
+	modPage := files["modo/mod1/_index.md"]
+	assert.Contains(t, modPage, "See [`Struct1.__init__(2)`](Struct1.md#__init__-2), "+
+		"[`Struct1.__init__(3)`](Struct1.md#__init__-3), "+
+		"[first func](func.md#func-1) and [`Struct1.single`](Struct1.md#single).")
+
+	structPage := files["modo/mod1/Struct1.md"]
+	assert.Contains(t, structPage, "### `__init__`\n\n#### `__init__` (1)\n")
+	assert.Contains(t, structPage, "#### `__init__` (3)\n")
+	assert.NotContains(t, structPage, "`single` (1)")
+
+	funcPage := files["modo/mod1/func.md"]
+	assert.Contains(t, funcPage, "## `func` (2)\n")
+}
+
+func TestOverloadRefsError(t *testing.T) {
+	proc := NewProcessor(nil, &TestFormatter{}, nil, &Config{Strict: true})
+	proc.linkExports = map[string]string{
+		"pkg.mod.Struct":          "pkg.Struct",
+		"pkg.mod.Struct.__init__": "pkg.Struct.__init__",
+	}
+	proc.linkOverloads = map[string][]*Function{
+		"pkg.Struct.__init__": {
+			{Args: []*Arg{{MemberName: MemberName{Name: "self"}, Type: "Self"}}},
+			{Args: []*Arg{{MemberName: MemberName{Name: "self"}, Type: "Self"}, {Type: "Int"}}},
+		},
+	}
+
+	out, err := proc.replaceRefs("A [pkg.mod.Struct.__init__( Int ) custom text].", []string{"pkg", "mod"}, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "A [pkg.Struct.__init__(2) custom text].", out)
+
+	out, err = proc.replaceRefs("A [pkg.mod.Struct.__init__()].", []string{"pkg", "mod"}, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "A [pkg.Struct.__init__(1)].", out)
+
+	_, err = proc.replaceRefs("A [pkg.mod.Struct.__init__(3)].", []string{"pkg", "mod"}, 2)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Can't resolve overload in cross ref 'pkg.mod.Struct.__init__(3)' in pkg.mod. The target has 2 overload(s)", err.Error())
+
+	_, err = proc.replaceRefs("A [pkg.mod.Struct(1)].", []string{"pkg", "mod"}, 2)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Can't resolve overload in cross ref 'pkg.mod.Struct(1)' in pkg.mod. The target is not a function", err.Error())
+}
+
+func TestSplitRef(t *testing.T) {
+	target, selector, text := splitRef("pkg.f(Int, String) some text")
+	assert.Equal(t, []string{"pkg.f", "(Int, String)", "some text"}, []string{target, selector, text})
+
+	target, selector, text = splitRef("pkg.f some (text)")
+	assert.Equal(t, []string{"pkg.f", "", "some (text)"}, []string{target, selector, text})
+
+	assert.Equal(t, []string{"Int", "Dict[K,V]", "List[Int]"}, splitTypes("Int, Dict[K, V], List[ Int ]"))
+	assert.Equal(t, []string{}, splitTypes(" "))
+}
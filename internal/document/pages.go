@@ -0,0 +1,181 @@
+package document
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Resolves doc transclusions and cross-refs in a handwritten Markdown page,
+// against the API docs prepared by the given processors.
+// Argument pageDir is the directory of the page, relative to the output directory.
+//
+// Brackets that don't start with the name of a processed package are left untouched,
+// as they are common in Markdown for other purposes.
+func (proc *Processor) processPage(text string, pageDir string, procs []*Processor) (string, error) {
+	text, err := proc.replacePageTranscludes(text, pageDir, procs)
+	if err != nil {
+		return "", err
+	}
+	return proc.replacePageRefs(text, pageDir, procs)
+}
+
+// Replaces transclusions like [[pkg.mod.Struct]] by the summary and description of the member.
+func (proc *Processor) replacePageTranscludes(text string, pageDir string, procs []*Processor) (string, error) {
+	indices, err := findLinks(text, transcludeRegex, false)
+	if err != nil {
+		return "", err
+	}
+	for i := len(indices) - 2; i >= 0; i -= 2 {
+		start, end := indices[i], indices[i+1]
+		link := text[start+1 : end-1]
+
+		p, ok := findPageProcessor(link, procs)
+		if !ok {
+			continue
+		}
+		elem, ok := p.allPaths[link]
+		if !ok {
+			if err := proc.warnOrError("Can't resolve doc transclusion '%s' in %s", link, pageDir); err != nil {
+				return "", err
+			}
+			continue
+		}
+		docs := []string{}
+		for _, d := range []string{elemSummary(elem), elemDescription(elem)} {
+			if d != "" {
+				docs = append(docs, d)
+			}
+		}
+		content, err := p.placeholdersToPageLinks(strings.Join(docs, "\n\n"), pageDir)
+		if err != nil {
+			return "", err
+		}
+		text = text[:start-1] + content + text[end+1:]
+	}
+	return text, nil
+}
+
+// Replaces cross-refs like [pkg.mod.Struct] by links relative to the page.
+func (proc *Processor) replacePageRefs(text string, pageDir string, procs []*Processor) (string, error) {
+	indices, err := findLinks(text, linkRegex, true)
+	if err != nil {
+		return "", err
+	}
+	elems := []string{pageDir}
+	for i := len(indices) - 2; i >= 0; i -= 2 {
+		start, end := indices[i], indices[i+1]
+		link := text[start+1 : end-1]
+		if strings.HasPrefix(link, ".") {
+			continue
+		}
+
+		p, ok := findPageProcessor(link, procs)
+		if !ok {
+			external, ok, err := proc.refToExternalLink(link)
+			if err != nil {
+				return "", err
+			}
+			if ok {
+				text = text[:start] + external + text[end:]
+			}
+			continue
+		}
+
+		placeholder, ok, err := p.refToPlaceholder(link, elems, 0, true)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			continue
+		}
+		content, ok, err := p.placeholderToPageLink(placeholder, pageDir)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			continue
+		}
+		text = text[:start] + content + text[end:]
+	}
+	return text, nil
+}
+
+// Replaces placeholders in doc-strings by links relative to a page.
+func (proc *Processor) placeholdersToPageLinks(text string, pageDir string) (string, error) {
+	indices, err := findLinks(text, linkRegex, true)
+	if err != nil {
+		return "", err
+	}
+	for i := len(indices) - 2; i >= 0; i -= 2 {
+		start, end := indices[i], indices[i+1]
+		content, ok, err := proc.placeholderToPageLink(text[start+1:end-1], pageDir)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			continue
+		}
+		text = text[:start] + content + text[end:]
+	}
+	return text, nil
+}
+
+// Creates a Markdown link from a placeholder, relative to a page.
+func (proc *Processor) placeholderToPageLink(placeholder string, pageDir string) (string, bool, error) {
+	entry, linkText, parts, ok, err := proc.placeholderToLink(placeholder, []string{}, 0, proc.Config.ShortLinks)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	pkgDir := proc.relativeDir([]string{path.Join(proc.Config.OutputDir, proc.subdir)})
+	baseDir, err := filepath.Rel(filepath.FromSlash(pageDir), filepath.FromSlash(pkgDir))
+	if err != nil {
+		return "", false, err
+	}
+	link := proc.targetLink(filepath.ToSlash(baseDir), parts, entry.Kind, entry.IsSection)
+	content := fmt.Sprintf("[%s](%s)", linkText, link)
+	if lp, ok := proc.Formatter.(LinkProcessor); ok {
+		if content, err = lp.ProcessLinks(content, pageDir, proc); err != nil {
+			return "", false, err
+		}
+	}
+	return content, true, nil
+}
+
+// Finds the processor for the package a cross-ref points into, by the first path element.
+func findPageProcessor(link string, procs []*Processor) (*Processor, bool) {
+	root, _, _ := strings.Cut(link, ".")
+	root, _, _ = strings.Cut(root, " ")
+	root, _, _ = strings.Cut(root, "(")
+	for _, p := range procs {
+		if p.Docs.Decl.GetName() == root {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func elemDescription(elem Named) string {
+	switch e := elem.(type) {
+	case *Package:
+		if e.MemberDescription == nil {
+			return ""
+		}
+		return e.Description
+	case *Module:
+		return e.Description
+	case *Struct:
+		return e.Description
+	case *Trait:
+		return e.Description
+	case *Alias:
+		return e.Description
+	case *Function:
+		if e.Description == "" && len(e.Overloads) > 0 {
+			return e.Overloads[0].Description
+		}
+		return e.Description
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package document
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessPages(t *testing.T) {
+	yml := `
+decl:
+  name: pkg
+  kind: package
+  modules:
+    - name: mod
+      kind: module
+      structs:
+        - name: Struct
+          kind: struct
+          summary: A struct.
+          description: See also [.Struct.method].
+          functions:
+            - name: method
+              kind: function
+              overloads:
+                - name: method
+                  kind: function
+`
+	docs, err := FromYAML([]byte(yml))
+	assert.Nil(t, err)
+
+	inDir := t.TempDir()
+	outDir := t.TempDir()
+	config := Config{OutputDir: outDir, Strict: true}
+
+	formatter := TestFormatter{}
+	templ, err := LoadTemplates(&formatter, "")
+	assert.Nil(t, err)
+	proc := NewProcessor(docs, &formatter, templ, &config)
+	assert.Nil(t, proc.PrepareDocs("api"))
+
+	assert.Nil(t, os.MkdirAll(path.Join(inDir, "guide"), 0755))
+	err = os.WriteFile(path.Join(inDir, "guide", "usage.md"), []byte(
+		"Use [pkg.mod.Struct] and [pkg.mod.Struct.method `method`].\n\n"+
+			"[[pkg.mod.Struct]]\n\n"+
+			"Keep [other] and [.Struct].\n",
+	), 0666)
+	assert.Nil(t, err)
+
+	_, err = ExtractTestsMarkdown(&config, &formatter, inDir, true, proc)
+	assert.Nil(t, err)
+
+	content, err := os.ReadFile(path.Join(outDir, "guide", "usage.md"))
+	assert.Nil(t, err)
+	assert.Equal(t,
+		"Use [`pkg.mod.Struct`](../api/pkg/mod/Struct.md) and [`method`](../api/pkg/mod/Struct.md#method).\n\n"+
+			"A struct.\n\nSee also [`pkg.mod.Struct.method`](../api/pkg/mod/Struct.md#method).\n\n"+
+			"Keep [other] and [.Struct].",
+		string(content))
+
+	err = os.WriteFile(path.Join(inDir, "guide", "usage.md"), []byte("See [pkg.mod.Foo].\n"), 0666)
+	assert.Nil(t, err)
+	_, err = ExtractTestsMarkdown(&config, &formatter, inDir, true, proc)
+	assert.NotNil(t, err)
+}
@@ -6,7 +6,8 @@ import (
 
 type elemPath struct {
 	Elements  []string
-	Kind      string
+	Kind      string // Kind used for link paths.
+	ElemKind  string // Actual kind of the member.
 	IsSection bool
 }
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"slices"
 	"strings"
 	"text/template"
 )
@@ -15,25 +16,63 @@ type Processor struct {
 	Formatter          Formatter
 	Docs               *Docs
 	ExportDocs         *Docs
-	allPaths           map[string]Named        // Full paths of all original members. Used to check whether all re-exports could be found.
-	linkTargets        map[string]elemPath     // Mapping from full (new) member paths to link strings.
-	linkExports        map[string]string       // Mapping from original to new member paths.
-	linkExportsReverse map[string]*exportError // Used to check for name collisions through re-exports.
-	renameExports      map[string]string       // Mapping from short to renamed member paths.
+	allPaths           map[string]Named           // Full paths of all original members. Used to check whether all re-exports could be found.
+	linkTargets        map[string]elemPath        // Mapping from full (new) member paths to link strings.
+	linkExports        map[string]string          // Mapping from original to new member paths.
+	linkExportsReverse map[string]*exportError    // Used to check for name collisions through re-exports.
+	renameExports      map[string]string          // Mapping from short to renamed member paths.
+	inventories        map[string]*inventory      // Loaded inventories of other projects, by package prefix.
+	linkOverloads      map[string][]*Function     // Overloads of functions and methods, by full (new) member path.
+	references         map[string]map[string]bool // Members referencing a member, by full (new) member paths.
+	implementors       map[string]map[string]bool // Full (new) paths of structs implementing a trait, by original trait path.
+	pagePaths          []pagePath                 // Paths of all members with their own page. Used to check for pages overwriting each other.
 	docTests           []*docTest
+	testFiles          []*DocTestFile
+	subdir             string
+	dryRunFiles        []string
 	writer             func(file, text string) error
 }
 
+type pagePath struct {
+	Member   string
+	Elements []string
+	Kind     string
+}
+
 type exportError struct {
 	NewPath  string
 	OldPaths []string
 }
 
 type docTest struct {
-	Name   string
-	Path   []string
-	Code   []string
-	Global []string
+	Name        string
+	Path        []string
+	Code        []string
+	Global      []string
+	Output      []string // Expected output, from blocks with attribute 'output'.
+	MainFile    string   // Main file of tests with expected output, relative to the project root.
+	RunCommand  string   // Command to run the test's main file, for tests with expected output.
+	Skip        bool     // Test is not written.
+	Raises      bool     // Test must raise an error.
+	RaisesMsg   string   // Text the message of the raised error must contain. Any error if empty.
+	RaisesFrom  int      // Start index of the code lines expected to raise.
+	RaisesTo    int      // End index of the code lines expected to raise. All code if zero.
+	CompileOnly bool     // Test code is type-checked, but not executed.
+	Markdown    string   // Markdown file the test was extracted from, if not from a docstring.
+	Line        int      // Line of the test's first code block in the docstring or Markdown file.
+}
+
+// DocTestFile holds the origin of a written doctest file.
+type DocTestFile struct {
+	File     string   `json:"file"`               // Path of the test file.
+	Main     string   `json:"main,omitempty"`     // Path of the program file of tests with expected output.
+	Name     string   `json:"name"`               // Name of the test.
+	Function string   `json:"function,omitempty"` // Name of the test function. Empty for tests with only global code.
+	Member   string   `json:"member,omitempty"`   // Dotted path of the member with the docstring the test was extracted from.
+	Markdown string   `json:"markdown,omitempty"` // Markdown file the test was extracted from.
+	Line     int      `json:"line"`               // Line of the test's first code block in the docstring or Markdown file.
+	Path     []string `json:"-"`                  // Path of the member or Markdown file the test was extracted from.
+	Raises   bool     `json:"-"`                  // Test must raise an error.
 }
 
 // NewProcessor creates a new Processor instance.
@@ -46,18 +85,23 @@ func NewProcessor(docs *Docs, f Formatter, t *template.Template, config *Config)
 // NewProcessorWithWriter creates a new Processor instance with a custom writer.
 func NewProcessorWithWriter(docs *Docs, f Formatter, t *template.Template, config *Config, writer func(file, text string) error) *Processor {
 	return &Processor{
-		Config:      config,
-		Template:    t,
-		Formatter:   f,
-		Docs:        docs,
-		writer:      writer,
-		allPaths:    map[string]Named{},
-		linkTargets: map[string]elemPath{},
+		Config:        config,
+		Template:      t,
+		Formatter:     f,
+		Docs:          docs,
+		writer:        writer,
+		allPaths:      map[string]Named{},
+		linkTargets:   map[string]elemPath{},
+		inventories:   map[string]*inventory{},
+		linkOverloads: map[string][]*Function{},
+		references:    map[string]map[string]bool{},
+		implementors:  map[string]map[string]bool{},
 	}
 }
 
 // PrepareDocs processes the API docs for subsequent rendering.
 func (proc *Processor) PrepareDocs(subdir string) error {
+	proc.subdir = subdir
 	err := proc.ExtractTests(subdir)
 	if err != nil {
 		return err
@@ -69,6 +113,9 @@ func (proc *Processor) PrepareDocs(subdir string) error {
 	}
 	// Collect all link target paths.
 	proc.collectPaths()
+	if err := proc.checkPageFiles(); err != nil {
+		return err
+	}
 	if !proc.Config.UseExports {
 		for k := range proc.linkTargets {
 			proc.linkExports[k] = k
@@ -79,6 +126,20 @@ func (proc *Processor) PrepareDocs(subdir string) error {
 		return err
 	}
 
+	// Collects referencing members, for backlinks.
+	proc.collectBacklinks()
+	// Collects structs implementing traits.
+	proc.collectImplementors()
+	// Collects default trait methods not overridden by structs.
+	if proc.Config.InheritedMethods {
+		proc.collectInheritedMethods()
+	}
+
+	// Resolves type paths to placeholders or external links.
+	if err := proc.processTypeLinks(); err != nil {
+		return err
+	}
+
 	if err := proc.processTranscludes(proc.Docs); err != nil {
 		return err
 	}
@@ -113,6 +174,16 @@ func (proc *Processor) ExtractTests(subdir string) error {
 	return nil
 }
 
+// TestFiles returns the doctest files written by the processor.
+func (proc *Processor) TestFiles() []*DocTestFile {
+	return proc.testFiles
+}
+
+// PackageDir returns the output directory of the processed package, relative to the output directory.
+func (proc *Processor) PackageDir() string {
+	return proc.relativeDir([]string{path.Join(proc.Config.OutputDir, proc.subdir), proc.ExportDocs.Decl.GetFileName()})
+}
+
 func (proc *Processor) writeFile(file, text string) error {
 	return proc.writer(file, text)
 }
@@ -136,7 +207,42 @@ func (proc *Processor) addLinkExport(oldPath, newPath []string) {
 }
 
 func (proc *Processor) addLinkTarget(elem Named, elPath, filePath []string, kind string, isSection bool) {
-	proc.linkTargets[strings.Join(elPath, ".")] = elemPath{Elements: filePath, Kind: kind, IsSection: isSection}
+	proc.linkTargets[strings.Join(elPath, ".")] = elemPath{Elements: filePath, Kind: kind, ElemKind: elemKind(elem, kind), IsSection: isSection}
+	if !isSection {
+		proc.pagePaths = append(proc.pagePaths, pagePath{Member: strings.Join(elPath, "."), Elements: filePath, Kind: kind})
+	}
+	switch e := elem.(type) {
+	case *Function:
+		proc.addOverloadTargets(e, elPath, filePath, kind, isSection)
+	case *Struct:
+		proc.addImplementor(e, elPath)
+	}
+}
+
+// checkPageFiles checks that no two members are written to the same file.
+// This can happen for formats with flattened page names, e.g. for a module and a function of the same name.
+func (proc *Processor) checkPageFiles() error {
+	pageFiles := map[string][]string{}
+	for _, p := range proc.pagePaths {
+		file := proc.Formatter.ToFilePath(path.Join(p.Elements...), p.Kind)
+		pageFiles[file] = append(pageFiles[file], fmt.Sprintf("%s %s", p.Kind, p.Member))
+	}
+	files := []string{}
+	for file, paths := range pageFiles {
+		if len(paths) > 1 {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	slices.Sort(files)
+	msg := strings.Builder{}
+	msg.WriteString(fmt.Sprintln("Name collisions in page files:"))
+	for _, file := range files {
+		msg.WriteString(fmt.Sprintf(" - %s: %s\n", file, strings.Join(pageFiles[file], ", ")))
+	}
+	return fmt.Errorf("%s", msg.String())
 }
 
 func (proc *Processor) addElementPath(elem Named, elPath, filePath []string, kind string, isSection bool) {
@@ -3,43 +3,66 @@ package document
 import (
 	"fmt"
 	"path"
+	"path/filepath"
 	"strings"
 )
 
 // Render generates documentation for the given docs and writes it to the output directory.
 func Render(docs *Docs, config *Config, form Formatter, subdir string) error {
-	t, err := LoadTemplates(form, config.SourceURLs[strings.ToLower(docs.Decl.Name)], config.TemplateDirs...)
+	proc, err := Prepare(docs, config, form, subdir)
 	if err != nil {
 		return err
 	}
-	if !config.DryRun {
-		proc := NewProcessor(docs, form, t, config)
-		return renderWith(config, proc, subdir)
-	}
+	return proc.Render()
+}
 
-	files := []string{}
-	proc := NewProcessorWithWriter(docs, form, t, config, func(file, text string) error {
-		files = append(files, file)
-		return nil
-	})
-	err = renderWith(config, proc, subdir)
+// Prepare creates a processor for the given docs and prepares them for rendering.
+// The returned processor can be used to resolve cross-refs in Markdown pages,
+// before the docs are written by [Processor.Render].
+func Prepare(docs *Docs, config *Config, form Formatter, subdir string) (*Processor, error) {
+	caseSensitiveSystem = !config.CaseInsensitive
+
+	t, err := LoadTemplates(form, config.SourceURLs[strings.ToLower(docs.Decl.Name)], config.TemplateDirs...)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	var proc *Processor
+	if config.DryRun {
+		proc = NewProcessorWithWriter(docs, form, t, config, func(file, text string) error {
+			proc.dryRunFiles = append(proc.dryRunFiles, file)
+			return nil
+		})
+	} else {
+		proc = NewProcessor(docs, form, t, config)
+	}
+	if err := proc.PrepareDocs(subdir); err != nil {
+		return nil, err
 	}
+	return proc, nil
+}
 
+// Render writes the docs prepared by [Prepare] to the output directory.
+func (proc *Processor) Render() error {
+	if err := renderPrepared(proc.Config, proc); err != nil {
+		return err
+	}
+	if !proc.Config.DryRun {
+		return nil
+	}
 	fmt.Println("Dry-run. Would write these files:")
-	for _, f := range files {
+	for _, f := range proc.dryRunFiles {
 		fmt.Println(f)
 	}
 	return nil
 }
 
 // ExtractTests extracts tests from the documentation.
-func ExtractTests(docs *Docs, config *Config, form Formatter, subdir string) error {
+// Returns the written test files.
+func ExtractTests(docs *Docs, config *Config, form Formatter, subdir string) ([]*DocTestFile, error) {
 	caseSensitiveSystem = !config.CaseInsensitive
 	t, err := LoadTemplates(form, config.SourceURLs[strings.ToLower(docs.Decl.Name)], config.TemplateDirs...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	var proc *Processor
 	if config.DryRun {
@@ -49,16 +72,22 @@ func ExtractTests(docs *Docs, config *Config, form Formatter, subdir string) err
 	} else {
 		proc = NewProcessor(docs, form, t, config)
 	}
-	return proc.ExtractTests(subdir)
+	if err := proc.ExtractTests(subdir); err != nil {
+		return nil, err
+	}
+	return proc.testFiles, nil
 }
 
 // ExtractTestsMarkdown extracts tests from markdown files.
-func ExtractTestsMarkdown(config *Config, form Formatter, baseDir string, build bool) error {
+// When building, cross-refs and doc transclusions in the files are resolved
+// against the packages of the given processors, as returned by [Prepare].
+// Returns the written test files.
+func ExtractTestsMarkdown(config *Config, form Formatter, baseDir string, build bool, procs ...*Processor) ([]*DocTestFile, error) {
 	caseSensitiveSystem = !config.CaseInsensitive
 
 	t, err := LoadTemplates(form, "", config.TemplateDirs...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	var proc *Processor
 	if config.DryRun {
@@ -68,7 +97,10 @@ func ExtractTestsMarkdown(config *Config, form Formatter, baseDir string, build
 	} else {
 		proc = NewProcessor(nil, form, t, config)
 	}
-	return proc.extractDocTestsMarkdown(baseDir, build)
+	if err := proc.extractDocTestsMarkdown(baseDir, build, procs); err != nil {
+		return nil, err
+	}
+	return proc.testFiles, nil
 }
 
 func renderWith(config *Config, proc *Processor, subdir string) error {
@@ -77,19 +109,33 @@ func renderWith(config *Config, proc *Processor, subdir string) error {
 	if err := proc.PrepareDocs(subdir); err != nil {
 		return err
 	}
+	return renderPrepared(config, proc)
+}
+
+func renderPrepared(config *Config, proc *Processor) error {
 	var missing []missingDocs
 	var stats missingStats
 	if config.ReportMissing {
 		missing = proc.Docs.Decl.checkMissing("", &stats)
 	}
 
-	outPath := path.Join(config.OutputDir, subdir)
+	outPath := path.Join(config.OutputDir, proc.subdir)
 	if err := renderPackage(proc.ExportDocs.Decl, []string{outPath}, proc); err != nil {
 		return err
 	}
 	if err := proc.Formatter.WriteAuxiliary(proc.ExportDocs.Decl, outPath, proc); err != nil {
 		return err
 	}
+	if config.SearchIndex {
+		if err := proc.writeSearchIndex(); err != nil {
+			return err
+		}
+	}
+	if config.Inventory {
+		if err := proc.writeInventory(); err != nil {
+			return err
+		}
+	}
 	if config.ReportMissing {
 		if err := reportMissing(proc.Docs.Decl.Name, missing, stats, config.Strict); err != nil {
 			return err
@@ -112,10 +158,6 @@ func renderElement(data interface {
 
 func renderPackage(p *Package, dir []string, proc *Processor) error {
 	newDir := appendNew(dir, p.GetFileName())
-	pkgPath := path.Join(newDir...)
-	if err := proc.mkDirs(pkgPath); err != nil {
-		return err
-	}
 
 	for _, pkg := range p.Packages {
 		if err := renderPackage(pkg, newDir, proc); err != nil {
@@ -152,9 +194,6 @@ func renderPackage(p *Package, dir []string, proc *Processor) error {
 
 func renderModule(mod *Module, dir []string, proc *Processor) error {
 	newDir := appendNew(dir, mod.GetFileName())
-	if err := proc.mkDirs(path.Join(newDir...)); err != nil {
-		return err
-	}
 
 	if err := renderList(mod.Structs, newDir, proc); err != nil {
 		return err
@@ -199,10 +238,28 @@ func linkAndWrite(text string, dir []string, modElems int, kind string, proc *Pr
 	if err != nil {
 		return err
 	}
-	outFile := proc.Formatter.ToFilePath(path.Join(dir...), kind)
+	if pp, ok := proc.Formatter.(PageProcessor); ok {
+		text, err = pp.ProcessPage(text, proc.relativeDir(dir[:modElems]), proc)
+		if err != nil {
+			return err
+		}
+	}
+	outFile := path.Join(dir[0], proc.Formatter.ToFilePath(path.Join(dir[1:]...), kind))
+	if err := proc.mkDirs(path.Dir(outFile)); err != nil {
+		return err
+	}
 	return proc.writeFile(outFile, text)
 }
 
+// relativeDir returns a page's base directory relative to the output directory.
+func (proc *Processor) relativeDir(dir []string) string {
+	base := dir[0]
+	if rel, err := filepath.Rel(filepath.Clean(proc.Config.OutputDir), filepath.Clean(base)); err == nil {
+		base = filepath.ToSlash(rel)
+	}
+	return path.Join(append([]string{base}, dir[1:]...)...)
+}
+
 func reportMissing(pkg string, missing []missingDocs, stats missingStats, strict bool) error {
 	if len(missing) == 0 {
 		fmt.Printf("Docstring coverage of package %s: 100%%\n", pkg)
@@ -263,16 +263,78 @@ decl:
 	assert.Nil(t, err)
 }
 
+type pageTestFormatter struct {
+	TestFormatter
+	dirs []string
+}
+
+func (f *pageTestFormatter) ProcessPage(text string, dir string, proc *Processor) (string, error) {
+	f.dirs = append(f.dirs, dir)
+	return text, nil
+}
+
+func TestRenderPageProcessor(t *testing.T) {
+	yml := `
+decl:
+  name: modo
+  kind: package
+  modules:
+    - name: mod1
+      kind: module
+      structs:
+        - name: Struct1
+          kind: struct
+`
+	docs, err := FromYAML([]byte(yml))
+	assert.Nil(t, err)
+
+	formatter := pageTestFormatter{}
+	templ, err := LoadTemplates(&formatter, "")
+	assert.Nil(t, err)
+	config := Config{OutputDir: "out"}
+	proc := NewProcessorWithWriter(docs, &formatter, templ, &config, func(file, text string) error {
+		return nil
+	})
+	proc.Config.DryRun = true
+
+	err = renderWith(&config, proc, "sub")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"sub/modo/mod1", "sub/modo/mod1", "sub/modo"}, formatter.dirs)
+	assert.Equal(t, "sub/modo", proc.PackageDir())
+}
+
 func createProcessor(t *testing.T, docs *Docs, useExports bool, files map[string]string) *Processor {
+	return createProcessorWithConfig(t, docs, &Config{UseExports: useExports, ShortLinks: true}, files)
+}
+
+func createProcessorWithConfig(t *testing.T, docs *Docs, config *Config, files map[string]string) *Processor {
 	formatter := TestFormatter{}
 	templ, err := LoadTemplates(&formatter, "")
 	assert.Nil(t, err)
-	return NewProcessorWithWriter(docs, &formatter, templ, &Config{UseExports: useExports, ShortLinks: true}, func(file, text string) error {
+	return NewProcessorWithWriter(docs, &formatter, templ, config, func(file, text string) error {
 		files[file] = text
 		return nil
 	})
 }
 
+// renderFiles renders the docs with the given config and returns the written files,
+// with paths relative to the output directory. Uses a temporary output directory if none is set.
+func renderFiles(t *testing.T, docs *Docs, config *Config) map[string]string {
+	if config.OutputDir == "" {
+		config.OutputDir = t.TempDir()
+	}
+	files := map[string]string{}
+	proc := createProcessorWithConfig(t, docs, config, files)
+	assert.Nil(t, renderWith(config, proc, ""))
+
+	relFiles := map[string]string{}
+	for file, text := range files {
+		relFiles[strings.TrimPrefix(file, config.OutputDir+"/")] = text
+	}
+	return relFiles
+}
+
 func TestRenderDry(t *testing.T) {
 	tmpDir := strings.ReplaceAll(t.TempDir(), "\\", "/")
 	config := Config{
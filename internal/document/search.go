@@ -0,0 +1,166 @@
+package document
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+const searchIndexFile = "search-index.json"
+
+// searchEntry is an entry in the search index.
+type searchEntry struct {
+	Path      string `json:"path"`
+	Kind      string `json:"kind"`
+	Summary   string `json:"summary"`
+	Signature string `json:"signature"`
+	Link      string `json:"link"`
+}
+
+// Collects search index entries for all members in the re-structured package.
+func (proc *Processor) collectSearchIndex() []searchEntry {
+	entries := []searchEntry{}
+	baseDir := proc.relativeDir([]string{path.Join(proc.Config.OutputDir, proc.subdir)})
+
+	pc := pathHelper{
+		AddPathFunc: func(elem Named, elPath, filePath []string, kind string, isSection bool) {
+			entries = append(entries, searchEntry{
+				Path:      strings.Join(elPath, "."),
+				Kind:      elemKind(elem, kind),
+				Summary:   proc.placeholdersToText(elemSummary(elem)),
+				Signature: elemSignature(elem),
+				Link:      proc.targetURL(baseDir, filePath, kind, isSection),
+			})
+		},
+		SetLink: false,
+	}
+	pc.collectPathsPackage(proc.ExportDocs.Decl, []string{}, []string{})
+
+	return entries
+}
+
+// Writes the search index to the output directory.
+// Entries of other packages already present in the file are retained.
+func (proc *Processor) writeSearchIndex() error {
+	entries := proc.collectSearchIndex()
+	if proc.Config.DryRun {
+		return nil
+	}
+
+	file := path.Join(proc.Config.OutputDir, searchIndexFile)
+	root := proc.ExportDocs.Decl.GetName()
+	if content, err := os.ReadFile(file); err == nil {
+		existing := []searchEntry{}
+		if err := json.Unmarshal(content, &existing); err != nil {
+			return fmt.Errorf("error parsing search index %s: %s", file, err.Error())
+		}
+		for _, e := range existing {
+			if e.Path != root && !strings.HasPrefix(e.Path, root+".") {
+				entries = append(entries, e)
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	slices.SortStableFunc(entries, func(a, b searchEntry) int { return strings.Compare(a.Path, b.Path) })
+
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(content, '\n'), 0644)
+}
+
+// Creates a link to a target, relative to the output directory.
+func (proc *Processor) targetLink(baseDir string, filePath []string, kind string, isSection bool) string {
+	if isSection {
+		return proc.Formatter.ToLinkPath(path.Join(baseDir, path.Join(filePath[:len(filePath)-1]...)), kind) +
+			filePath[len(filePath)-1]
+	}
+	return proc.Formatter.ToLinkPath(path.Join(baseDir, path.Join(filePath...)), kind)
+}
+
+// Creates a URL of a target, relative to the output directory.
+// Unlike [Processor.targetLink], the result is independent of format-specific link syntax.
+func (proc *Processor) targetURL(baseDir string, filePath []string, kind string, isSection bool) string {
+	toURL := proc.Formatter.ToFilePath
+	if f, ok := proc.Formatter.(URLFormatter); ok {
+		toURL = f.ToURL
+	}
+	if isSection {
+		anchor := filePath[len(filePath)-1]
+		if s, ok := proc.Formatter.(Slugger); ok {
+			anchor = "#" + s.Slug(strings.TrimPrefix(anchor, "#"))
+		}
+		return toURL(path.Join(baseDir, path.Join(filePath[:len(filePath)-1]...)), kind) + anchor
+	}
+	return toURL(path.Join(baseDir, path.Join(filePath...)), kind)
+}
+
+// Replaces cross-ref placeholders by their plain text.
+func (proc *Processor) placeholdersToText(text string) string {
+	indices, err := findLinks(text, linkRegex, true)
+	if err != nil || len(indices) == 0 {
+		return text
+	}
+	for i := len(indices) - 2; i >= 0; i -= 2 {
+		start, end := indices[i], indices[i+1]
+		linkParts := strings.SplitN(text[start+1:end-1], " ", 2)
+		if _, ok := proc.linkTargets[linkParts[0]]; !ok {
+			continue
+		}
+		linkText := fmt.Sprintf("`%s`", linkParts[0])
+		if len(linkParts) > 1 {
+			linkText = linkParts[1]
+		}
+		text = text[:start] + linkText + text[end:]
+	}
+	return text
+}
+
+func elemKind(elem Named, kind string) string {
+	if k, ok := elem.(Kinded); ok && k.GetKind() != "" {
+		return k.GetKind()
+	}
+	return kind
+}
+
+func elemSummary(elem Named) string {
+	switch e := elem.(type) {
+	case *Package:
+		if e.MemberSummary == nil {
+			return ""
+		}
+		return e.Summary
+	case *Function:
+		if e.Summary == "" && len(e.Overloads) > 0 {
+			return e.Overloads[0].Summary
+		}
+		return e.Summary
+	case Summarized:
+		return e.GetSummary()
+	}
+	return ""
+}
+
+func elemSignature(elem Named) string {
+	switch e := elem.(type) {
+	case *Struct:
+		return e.Signature
+	case *Alias:
+		return e.Signature
+	case *Function:
+		if e.Signature == "" && len(e.Overloads) > 0 {
+			return e.Overloads[0].Signature
+		}
+		return e.Signature
+	case *Field:
+		return e.Type
+	case *Parameter:
+		return e.Type
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package document
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteSearchIndex(t *testing.T) {
+	yml := `
+decl:
+  name: modo
+  kind: package
+  summary: The package
+  modules:
+    - name: mod1
+      kind: module
+      summary: See [.Struct1]
+      aliases:
+        - name: A
+          kind: alias
+          summary: An alias
+      structs:
+        - name: Struct1
+          kind: struct
+          summary: A struct
+          signature: struct Struct1
+          fields:
+            - name: x
+              kind: field
+              type: Int
+          functions:
+            - name: f
+              kind: function
+              overloads:
+                - name: f
+                  kind: function
+                  summary: A method
+                  signature: f(self)
+`
+	docs, err := FromYAML([]byte(yml))
+	assert.Nil(t, err)
+
+	outDir := t.TempDir()
+	existing := []searchEntry{
+		{Path: "other", Kind: "package", Link: "other/_index.md"},
+		{Path: "modo.old", Kind: "module", Link: "modo/old/_index.md"},
+	}
+	content, err := json.Marshal(existing)
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(path.Join(outDir, searchIndexFile), content, 0644))
+
+	formatter := TestFormatter{}
+	templ, err := LoadTemplates(&formatter, "")
+	assert.Nil(t, err)
+	config := Config{OutputDir: outDir, SearchIndex: true}
+	proc := NewProcessorWithWriter(docs, &formatter, templ, &config, func(file, text string) error {
+		return nil
+	})
+
+	err = renderWith(&config, proc, "sub")
+	assert.Nil(t, err)
+
+	content, err = os.ReadFile(path.Join(outDir, searchIndexFile))
+	assert.Nil(t, err)
+	entries := []searchEntry{}
+	assert.Nil(t, json.Unmarshal(content, &entries))
+
+	assert.Equal(t, []searchEntry{
+		{Path: "modo", Kind: "package", Summary: "The package", Link: "sub/modo/_index.md"},
+		{Path: "modo.mod1", Kind: "module", Summary: "See `modo.mod1.Struct1`", Link: "sub/modo/mod1/_index.md"},
+		{Path: "modo.mod1.A", Kind: "alias", Summary: "An alias", Signature: "A", Link: "sub/modo/mod1/_index.md#aliases"},
+		{Path: "modo.mod1.Struct1", Kind: "struct", Summary: "A struct", Signature: "struct Struct1", Link: "sub/modo/mod1/Struct1.md"},
+		{Path: "modo.mod1.Struct1.f", Kind: "function", Summary: "A method", Signature: "f(self)", Link: "sub/modo/mod1/Struct1.md#f"},
+		{Path: "modo.mod1.Struct1.x", Kind: "field", Signature: "Int", Link: "sub/modo/mod1/Struct1.md#fields"},
+		{Path: "other", Kind: "package", Link: "other/_index.md"},
+	}, entries)
+}
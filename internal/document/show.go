@@ -0,0 +1,100 @@
+package document
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Show renders the documentation of a single member, given by its dotted path, and writes it to out.
+// Cross-refs are rendered as plain text.
+// Methods are rendered alone, while other sections like fields are rendered with their parent's page.
+func Show(docs *Docs, config *Config, form Formatter, memberPath string, out io.Writer) error {
+	caseSensitiveSystem = !config.CaseInsensitive
+	t, err := LoadTemplates(form, config.SourceURLs[strings.ToLower(docs.Decl.Name)], config.TemplateDirs...)
+	if err != nil {
+		return err
+	}
+	proc := NewProcessorWithWriter(docs, form, t, config, func(file, text string) error {
+		return nil
+	})
+	if err := proc.PrepareDocs(""); err != nil {
+		return err
+	}
+
+	text, dir, err := proc.renderMember(memberPath)
+	if err != nil {
+		return err
+	}
+	if pp, ok := form.(PageProcessor); ok {
+		text, err = pp.ProcessPage(text, dir, proc)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(out, text)
+	return err
+}
+
+// Renders the member with the given path, using the lookup of link targets.
+// Also returns the base directory of the member's page, relative to the output directory.
+func (proc *Processor) renderMember(memberPath string) (string, string, error) {
+	target, ok := proc.linkTargets[memberPath]
+	if !ok {
+		// Fall back to the original path of re-exported members.
+		if newPath, found := proc.linkExports[memberPath]; found {
+			memberPath = newPath
+			target, ok = proc.linkTargets[memberPath]
+		}
+	}
+	if !ok {
+		return "", "", fmt.Errorf("member '%s' not found in package %s", memberPath, proc.ExportDocs.Decl.GetName())
+	}
+	pagePath := target.Elements
+	if target.IsSection {
+		pagePath = pagePath[:len(pagePath)-1]
+	}
+
+	var page, section Named
+	pc := pathHelper{
+		AddPathFunc: func(elem Named, elPath, filePath []string, kind string, isSection bool) {
+			if !isSection && slices.Equal(filePath, pagePath) {
+				page = elem
+			}
+			if isSection && strings.Join(elPath, ".") == memberPath {
+				section = elem
+			}
+		},
+		SetLink: false,
+	}
+	pc.collectPathsPackage(proc.ExportDocs.Decl, []string{}, []string{})
+
+	var text string
+	if method, ok := section.(*Function); ok {
+		b := strings.Builder{}
+		if err := proc.Template.ExecuteTemplate(&b, "method", method); err != nil {
+			return "", "", err
+		}
+		text = b.String()
+	} else {
+		elem, ok := page.(interface {
+			Named
+			Kinded
+		})
+		if !ok {
+			return "", "", fmt.Errorf("no page found for member '%s'", memberPath)
+		}
+		var err error
+		if text, err = renderElement(elem, proc); err != nil {
+			return "", "", err
+		}
+	}
+
+	dir := pagePath
+	if kind, ok := page.(Kinded); ok && kind.GetKind() != "package" && kind.GetKind() != "module" {
+		dir = dir[:len(dir)-1]
+	}
+	return proc.placeholdersToText(text), path.Join(dir...), nil
+}
@@ -0,0 +1,113 @@
+package document
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShow(t *testing.T) {
+	yml := `
+decl:
+  name: modo
+  kind: package
+  summary: The package
+  modules:
+    - name: mod1
+      kind: module
+      summary: See [.Struct1]
+      structs:
+        - name: Struct1
+          kind: struct
+          summary: A struct
+          signature: struct Struct1
+          fields:
+            - name: x
+              kind: field
+              type: Int
+          functions:
+            - name: f
+              kind: function
+              overloads:
+                - name: f
+                  kind: function
+                  summary: A method, see [.Struct1]
+                  signature: f(self)
+`
+	formatter := TestFormatter{}
+
+	docs, err := FromYAML([]byte(yml))
+	assert.Nil(t, err)
+	b := strings.Builder{}
+	err = Show(docs, &Config{}, &formatter, "modo.mod1.Struct1.f", &b)
+	assert.Nil(t, err)
+	assert.Contains(t, b.String(), "### `f`")
+	assert.Contains(t, b.String(), "A method, see `modo.mod1.Struct1`")
+	assert.NotContains(t, b.String(), "# `Struct1`")
+
+	docs, err = FromYAML([]byte(yml))
+	assert.Nil(t, err)
+	b.Reset()
+	err = Show(docs, &Config{}, &formatter, "modo.mod1.Struct1.x", &b)
+	assert.Nil(t, err)
+	assert.Contains(t, b.String(), "# `Struct1`")
+	assert.Contains(t, b.String(), "A struct")
+
+	docs, err = FromYAML([]byte(yml))
+	assert.Nil(t, err)
+	b.Reset()
+	err = Show(docs, &Config{}, &formatter, "modo.mod1", &b)
+	assert.Nil(t, err)
+	assert.Contains(t, b.String(), "See `modo.mod1.Struct1`")
+
+	docs, err = FromYAML([]byte(yml))
+	assert.Nil(t, err)
+	err = Show(docs, &Config{}, &formatter, "modo.mod1.Struct2", &b)
+	assert.NotNil(t, err)
+	assert.Equal(t, "member 'modo.mod1.Struct2' not found in package modo", err.Error())
+}
+
+func TestShowExports(t *testing.T) {
+	yml := `
+decl:
+  name: modo
+  kind: package
+  summary: The package
+  description: |
+    Exports:
+     - mod1.Struct1
+  modules:
+    - name: mod1
+      kind: module
+      structs:
+        - name: Struct1
+          kind: struct
+          summary: A struct
+          signature: struct Struct1
+          functions:
+            - name: f
+              kind: function
+              overloads:
+                - name: f
+                  kind: function
+                  summary: A method
+                  signature: f(self)
+`
+	formatter := TestFormatter{}
+
+	docs, err := FromYAML([]byte(yml))
+	assert.Nil(t, err)
+	b := strings.Builder{}
+	err = Show(docs, &Config{UseExports: true, ShortLinks: true}, &formatter, "modo.mod1.Struct1.f", &b)
+	assert.Nil(t, err)
+	assert.Contains(t, b.String(), "### `f`")
+	assert.Contains(t, b.String(), "A method")
+
+	docs, err = FromYAML([]byte(yml))
+	assert.Nil(t, err)
+	b.Reset()
+	err = Show(docs, &Config{UseExports: true, ShortLinks: true}, &formatter, "modo.Struct1.f", &b)
+	assert.Nil(t, err)
+	assert.Contains(t, b.String(), "A method")
+}
@@ -0,0 +1,96 @@
+package document
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const maxSuggestions = 3
+
+// Creates a hint for an unresolved cross ref, to be appended to the warning.
+// Reports whether the target exists but was not re-exported,
+// or otherwise suggests the closest resolvable paths.
+func (proc *Processor) unresolvedRefHint(fullLink string) string {
+	if _, ok := proc.allPaths[fullLink]; ok {
+		return ". The member exists, but is not re-exported"
+	}
+	suggestions := proc.suggestPaths(fullLink)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(". Did you mean '%s'?", strings.Join(suggestions, "', '"))
+}
+
+// Finds the closest cross ref targets for the given path.
+// Targets with the same trailing member names are preferred,
+// followed by targets with the smallest edit distance.
+func (proc *Processor) suggestPaths(link string) []string {
+	type candidate struct {
+		Path     string
+		Suffix   int
+		Distance int
+	}
+	lower := strings.ToLower(link)
+	linkParts := strings.Split(lower, ".")
+	maxDist := max(2, len(lower)/4)
+
+	candidates := []candidate{}
+	for p := range proc.linkExports {
+		pLower := strings.ToLower(p)
+		suffix := commonSuffix(linkParts, strings.Split(pLower, "."))
+		dist := editDistance(lower, pLower)
+		if suffix == 0 && dist > maxDist {
+			continue
+		}
+		candidates = append(candidates, candidate{Path: p, Suffix: suffix, Distance: dist})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Suffix != b.Suffix {
+			return a.Suffix > b.Suffix
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		return a.Path < b.Path
+	})
+
+	result := []string{}
+	for i := 0; i < len(candidates) && i < maxSuggestions; i++ {
+		result = append(result, candidates[i].Path)
+	}
+	return result
+}
+
+// Returns the number of equal trailing elements of two paths.
+func commonSuffix(a, b []string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return n
+}
+
+// Calculates the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
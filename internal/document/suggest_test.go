@@ -0,0 +1,55 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("abc", "abc"))
+	assert.Equal(t, 1, editDistance("Struct", "Strct"))
+	assert.Equal(t, 2, editDistance("abc", "bca"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+}
+
+func TestUnresolvedRefHint(t *testing.T) {
+	proc := NewProcessor(nil, &TestFormatter{}, nil, &Config{Strict: true})
+	proc.linkExports = map[string]string{
+		"pkg":                     "pkg",
+		"pkg.mod":                 "pkg.mod",
+		"pkg.mod.Struct":          "pkg.Struct",
+		"pkg.mod.Struct.method":   "pkg.Struct.method",
+		"pkg.mod.Other":           "pkg.Other",
+		"pkg.mod.Other.method":    "pkg.Other.method",
+		"pkg.mod.function":        "pkg.function",
+		"pkg.other.Unrelated":     "pkg.Unrelated",
+		"pkg.other.Unrelated.foo": "pkg.Unrelated.foo",
+	}
+	proc.allPaths = map[string]Named{
+		"pkg.mod.Hidden": &Struct{},
+	}
+
+	_, err := proc.replaceRefs("A [pkg.mod.Strct].", []string{"pkg", "mod"}, 2)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Can't resolve cross ref (abs) 'pkg.mod.Strct' in pkg.mod. Did you mean 'pkg.mod.Struct'?", err.Error())
+
+	_, err = proc.replaceRefs("A [pkg.Struct.method].", []string{"pkg", "mod"}, 2)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Can't resolve cross ref (abs) 'pkg.Struct.method' in pkg.mod. "+
+		"Did you mean 'pkg.mod.Struct.method', 'pkg.mod.Other.method'?", err.Error())
+
+	_, err = proc.replaceRefs("A [.Strukt.method].", []string{"pkg", "mod"}, 2)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Can't resolve cross ref (rel) '.Strukt.method' (pkg.mod.Strukt.method) in pkg.mod. "+
+		"Did you mean 'pkg.mod.Struct.method', 'pkg.mod.Other.method'?", err.Error())
+
+	_, err = proc.replaceRefs("A [.Hidden].", []string{"pkg", "mod"}, 2)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Can't resolve cross ref (rel) '.Hidden' (pkg.mod.Hidden) in pkg.mod. "+
+		"The member exists, but is not re-exported", err.Error())
+
+	_, err = proc.replaceRefs("A [xyz.abc].", []string{"pkg", "mod"}, 2)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Can't resolve cross ref (abs) 'xyz.abc' in pkg.mod", err.Error())
+}
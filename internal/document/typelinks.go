@@ -0,0 +1,140 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Resolves the paths of arg, parameter, return and parent trait types to links.
+// Runs on the original structure, after the link targets were collected.
+func (proc *Processor) processTypeLinks() error {
+	var err error
+	pc := pathHelper{
+		AddPathFunc: func(elem Named, elPath, filePath []string, kind string, isSection bool) {
+			if err != nil {
+				return
+			}
+			err = proc.addTypeLinks(elem)
+		},
+		SetLink: false,
+	}
+	pc.collectPathsPackage(proc.Docs.Decl, []string{}, []string{})
+	return err
+}
+
+func (proc *Processor) addTypeLinks(elem Named) error {
+	var err error
+	switch e := elem.(type) {
+	case *Function:
+		if err = proc.addFunctionTypeLinks(e); err != nil {
+			return err
+		}
+		for _, o := range e.Overloads {
+			if err = proc.addFunctionTypeLinks(o); err != nil {
+				return err
+			}
+		}
+	case *Struct:
+		if err = proc.addParameterTypeLinks(e.Parameters); err != nil {
+			return err
+		}
+		return proc.addParentTraitLinks(e.ParentTraits)
+	case *Trait:
+		return proc.addParentTraitLinks(e.ParentTraits)
+	case *Alias:
+		return proc.addParameterTypeLinks(e.Parameters)
+	}
+	return nil
+}
+
+func (proc *Processor) addFunctionTypeLinks(f *Function) error {
+	var err error
+	for _, a := range f.Args {
+		if a.TypeLink, err = proc.typeOrTraitsLink(a.Type, a.Path, a.Traits); err != nil {
+			return err
+		}
+	}
+	if err = proc.addParameterTypeLinks(f.Parameters); err != nil {
+		return err
+	}
+	if f.Returns != nil {
+		if f.Returns.TypeLink, err = proc.typeLink(f.Returns.Type, f.Returns.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (proc *Processor) addParameterTypeLinks(params []*Parameter) error {
+	var err error
+	for _, p := range params {
+		if p.TypeLink, err = proc.typeOrTraitsLink(p.Type, p.Path, p.Traits); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (proc *Processor) addParentTraitLinks(traits []*ParentTrait) error {
+	var err error
+	for _, t := range traits {
+		if t.TypeLink, err = proc.typeLink(t.Name, t.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Creates a link for a type, or for the traits that bound it if the type itself can't be resolved.
+// Traits are joined by " & ", with unresolved traits as plain code.
+func (proc *Processor) typeOrTraitsLink(typ, typePath string, traits []*TraitEntry) (string, error) {
+	link, err := proc.typeLink(typ, typePath)
+	if err != nil || link != "" || len(traits) == 0 {
+		return link, err
+	}
+	links := make([]string, 0, len(traits))
+	anyLink := false
+	for _, t := range traits {
+		link, err := proc.typeLink(t.Type, t.Path)
+		if err != nil {
+			return "", err
+		}
+		if link == "" {
+			link = fmt.Sprintf("`%s`", t.Type)
+		} else {
+			anyLink = true
+		}
+		links = append(links, link)
+	}
+	if !anyLink {
+		return "", nil
+	}
+	return strings.Join(links, " & "), nil
+}
+
+// Creates a link for a type with the given full path.
+// Types of the processed package become placeholders, to be replaced by links on rendering.
+// Other types are linked via inventories, if configured.
+// Returns an empty string if the type can't be resolved.
+func (proc *Processor) typeLink(typ, typePath string) (string, error) {
+	if typePath == "" || typ == "" {
+		return "", nil
+	}
+	if newPath, ok := proc.linkExports[typePath]; ok {
+		return fmt.Sprintf("[%s `%s`]", newPath, typ), nil
+	}
+
+	prefix, config, ok := proc.findInventory(typePath)
+	if !ok {
+		return "", nil
+	}
+	inv, err := proc.loadInventory(prefix, config)
+	if err != nil {
+		return "", err
+	}
+	obj, ok := inv.Objects[typePath]
+	if !ok {
+		return "", nil
+	}
+	return fmt.Sprintf("[`%s`](%s)", typ, inventoryURL(config, &obj)), nil
+}
@@ -0,0 +1,85 @@
+package document
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeLinks(t *testing.T) {
+	yml := `
+decl:
+  name: modo
+  kind: package
+  modules:
+    - name: mod1
+      kind: module
+      structs:
+        - name: Struct1
+          kind: struct
+      traits:
+        - name: Trait1
+          kind: trait
+      functions:
+        - name: func
+          kind: function
+          overloads:
+            - name: func
+              kind: function
+              args:
+                - name: a
+                  kind: arg
+                  type: Struct1[Int]
+                  path: modo.mod1.Struct1
+                - name: b
+                  kind: arg
+                  type: T
+                  traits:
+                    - type: Trait1
+                      path: modo.mod1.Trait1
+                    - type: Copyable
+                      path: stdlib.Copyable
+              parameters:
+                - name: T
+                  kind: parameter
+                  type: Int
+                  path: stdlib.Int
+              returns:
+                type: Foo[Struct1]
+                path: otherpkg.Foo
+`
+	docs, err := FromYAML([]byte(yml))
+	assert.Nil(t, err)
+	docs.Decl.Modules[0].Structs[0].ParentTraits = []*ParentTrait{
+		{Name: "Trait1", Path: "modo.mod1.Trait1"},
+		{Name: "Sized", Path: "stdlib.Sized"},
+	}
+
+	dir := t.TempDir()
+	invFile := path.Join(dir, "objects.json")
+	assert.Nil(t, os.WriteFile(invFile, []byte(`{
+  "version": 1,
+  "objects": {
+    "otherpkg.Foo": {"kind": "struct", "url": "otherpkg/Foo.md"}
+  }
+}`), 0644))
+
+	files := renderFiles(t, docs, &Config{
+		OutputDir:  dir,
+		UseExports: false,
+		Inventories: map[string]InventoryConfig{
+			"otherpkg": {URL: "https://example.com/docs", Inventory: invFile},
+		},
+	})
+
+	funcPage := files["modo/mod1/func.md"]
+	assert.Contains(t, funcPage, "- **a** ([`Struct1[Int]`](Struct1.md))")
+	assert.Contains(t, funcPage, "- **b** ([`Trait1`](Trait1.md) & `Copyable`)")
+	assert.Contains(t, funcPage, "- **T** (`Int`)")
+	assert.Contains(t, funcPage, "[`Foo[Struct1]`](https://example.com/docs/otherpkg/Foo.md)")
+
+	structPage := files["modo/mod1/Struct1.md"]
+	assert.Contains(t, structPage, "[`Trait1`](Trait1.md), `Sized`")
+}
@@ -67,8 +67,9 @@ func warnOrError(strict bool, pattern string, args ...any) error {
 func LoadTemplates(f Formatter, sourceURL string, additional ...string) (*template.Template, error) {
 	templ := template.New("all")
 	templ = templ.Funcs(template.FuncMap{
-		"toLink":    f.ToLinkPath,
-		"sourceUrl": func() string { return sourceURL },
+		"toLink":     f.ToLinkPath,
+		"sourceUrl":  func() string { return sourceURL },
+		"mojoString": mojoString,
 	})
 	templ, err := templ.ParseFS(assets.Templates, "templates/*.*", "templates/**/*.*")
 	if err != nil {
@@ -230,3 +231,9 @@ func repoToTitleAndPages(repo string) (string, string) {
 	}
 	return title, pages
 }
+
+// mojoString creates a Mojo string literal from a string.
+func mojoString(s string) string {
+	r := strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n", "\r", "\\r", "\t", "\\t")
+	return "\"" + r.Replace(s) + "\""
+}
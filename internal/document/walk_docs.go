@@ -2,10 +2,22 @@ package document
 
 type walkFunc = func(text string, elems []string, modElems int) (string, error)
 type nameFunc = func(elem Named) string
+type descriptionFunc = func(summary, text string, elems []string, modElems int) (string, error)
 
 type walker struct {
 	Func     walkFunc
 	NameFunc nameFunc
+	// DescriptionFunc is used instead of Func for member descriptions, if set.
+	// It additionally receives the member's summary.
+	DescriptionFunc descriptionFunc
+}
+
+// Applies the walk function to the description of a member with the given summary.
+func (w *walker) description(summary, text string, elems []string, modElems int) (string, error) {
+	if w.DescriptionFunc == nil {
+		return w.Func(text, elems, modElems)
+	}
+	return w.DescriptionFunc(summary, text, elems, modElems)
 }
 
 func (w *walker) walkAllDocStrings(docs *Docs) error {
@@ -19,7 +31,7 @@ func (w *walker) walkAllDocStringsPackage(p *Package, elems []string) error {
 	if p.Summary, err = w.Func(p.Summary, newElems, len(newElems)); err != nil {
 		return err
 	}
-	if p.Description, err = w.Func(p.Description, newElems, len(newElems)); err != nil {
+	if p.Description, err = w.description(p.Summary, p.Description, newElems, len(newElems)); err != nil {
 		return err
 	}
 
@@ -64,7 +76,7 @@ func (w *walker) walkAllDocStringsModule(m *Module, elems []string) error {
 	if m.Summary, err = w.Func(m.Summary, newElems, len(newElems)); err != nil {
 		return err
 	}
-	if m.Description, err = w.Func(m.Description, newElems, len(newElems)); err != nil {
+	if m.Description, err = w.description(m.Summary, m.Description, newElems, len(newElems)); err != nil {
 		return err
 	}
 
@@ -98,7 +110,7 @@ func (w *walker) walkAllDocStringsStruct(s *Struct, elems []string) error {
 	if s.Summary, err = w.Func(s.Summary, newElems, len(elems)); err != nil {
 		return err
 	}
-	if s.Description, err = w.Func(s.Description, newElems, len(elems)); err != nil {
+	if s.Description, err = w.description(s.Summary, s.Description, newElems, len(elems)); err != nil {
 		return err
 	}
 	if s.Deprecated, err = w.Func(s.Deprecated, newElems, len(elems)); err != nil {
@@ -109,7 +121,7 @@ func (w *walker) walkAllDocStringsStruct(s *Struct, elems []string) error {
 		if a.Summary, err = w.Func(a.Summary, newElems, len(elems)); err != nil {
 			return err
 		}
-		if a.Description, err = w.Func(a.Description, newElems, len(elems)); err != nil {
+		if a.Description, err = w.description(a.Summary, a.Description, newElems, len(elems)); err != nil {
 			return err
 		}
 		if a.Deprecated, err = w.Func(a.Deprecated, newElems, len(elems)); err != nil {
@@ -125,7 +137,7 @@ func (w *walker) walkAllDocStringsStruct(s *Struct, elems []string) error {
 		if f.Summary, err = w.Func(f.Summary, newElems, len(elems)); err != nil {
 			return err
 		}
-		if f.Description, err = w.Func(f.Description, newElems, len(elems)); err != nil {
+		if f.Description, err = w.description(f.Summary, f.Description, newElems, len(elems)); err != nil {
 			return err
 		}
 	}
@@ -145,7 +157,7 @@ func (w *walker) walkAllDocStringsTrait(tr *Trait, elems []string) error {
 	if tr.Summary, err = w.Func(tr.Summary, newElems, len(elems)); err != nil {
 		return err
 	}
-	if tr.Description, err = w.Func(tr.Description, newElems, len(elems)); err != nil {
+	if tr.Description, err = w.description(tr.Summary, tr.Description, newElems, len(elems)); err != nil {
 		return err
 	}
 	if tr.Deprecated, err = w.Func(tr.Deprecated, newElems, len(elems)); err != nil {
@@ -156,7 +168,7 @@ func (w *walker) walkAllDocStringsTrait(tr *Trait, elems []string) error {
 		if a.Summary, err = w.Func(a.Summary, newElems, len(elems)); err != nil {
 			return err
 		}
-		if a.Description, err = w.Func(a.Description, newElems, len(elems)); err != nil {
+		if a.Description, err = w.description(a.Summary, a.Description, newElems, len(elems)); err != nil {
 			return err
 		}
 		if a.Deprecated, err = w.Func(a.Deprecated, newElems, len(elems)); err != nil {
@@ -174,7 +186,7 @@ func (w *walker) walkAllDocStringsTrait(tr *Trait, elems []string) error {
 		if f.Summary, err = w.Func(f.Summary, newElems, len(elems)); err != nil {
 			return err
 		}
-		if f.Description, err = w.Func(f.Description, newElems, len(elems)); err != nil {
+		if f.Description, err = w.description(f.Summary, f.Description, newElems, len(elems)); err != nil {
 			return err
 		}
 	}
@@ -194,7 +206,7 @@ func (w *walker) walkAllDocStringsFunction(f *Function, elems []string) error {
 	if f.Summary, err = w.Func(f.Summary, newElems, len(elems)); err != nil {
 		return err
 	}
-	if f.Description, err = w.Func(f.Description, newElems, len(elems)); err != nil {
+	if f.Description, err = w.description(f.Summary, f.Description, newElems, len(elems)); err != nil {
 		return err
 	}
 	if f.Deprecated, err = w.Func(f.Deprecated, newElems, len(elems)); err != nil {
@@ -240,7 +252,7 @@ func (w *walker) walkAllDocStringsModuleAlias(a *Alias, elems []string) error {
 	if a.Summary, err = w.Func(a.Summary, newElems, len(elems)); err != nil {
 		return err
 	}
-	if a.Description, err = w.Func(a.Description, newElems, len(elems)); err != nil {
+	if a.Description, err = w.description(a.Summary, a.Description, newElems, len(elems)); err != nil {
 		return err
 	}
 	if a.Deprecated, err = w.Func(a.Deprecated, newElems, len(elems)); err != nil {
@@ -254,7 +266,7 @@ func (w *walker) walkAllDocStringsMethod(f *Function, elems []string) error {
 	if f.Summary, err = w.Func(f.Summary, elems, len(elems)-1); err != nil {
 		return err
 	}
-	if f.Description, err = w.Func(f.Description, elems, len(elems)-1); err != nil {
+	if f.Description, err = w.description(f.Summary, f.Description, elems, len(elems)-1); err != nil {
 		return err
 	}
 	if f.Deprecated, err = w.Func(f.Deprecated, elems, len(elems)-1); err != nil {
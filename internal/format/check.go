@@ -0,0 +1,294 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mlange-42/modo/internal/document"
+)
+
+var checkLinkRegex = regexp.MustCompile(`!?\[[^\]]*\]\((\{\{<\s*ref\s+"[^"]*"\s*>\}\}[^)\s]*|[^)\s]+)[^)]*\)`)
+var checkRefDefRegex = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:\s*(\S+)`)
+var checkHugoRefRegex = regexp.MustCompile(`^\{\{<\s*ref\s+"([^"]*)"\s*>\}\}(.*)$`)
+var checkHeadingIDRegex = regexp.MustCompile(`\s*\{#([^}\s]+)\}\s*$`)
+var checkHTMLAnchorRegex = regexp.MustCompile(`<a\s+(?:id|name)="([^"]+)"`)
+var checkInlineCodeRegex = regexp.MustCompile("`[^`]*`")
+var checkSchemeRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+var checkDocRoleRegex = regexp.MustCompile("\\{doc\\}`(?:[^`]*<([^<>`]+)>|([^`<]+))`")
+
+// BrokenLink is a link in a Markdown file that points to a missing file or anchor.
+type BrokenLink struct {
+	File   string
+	Line   int
+	Link   string
+	Reason string
+}
+
+func (l *BrokenLink) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", l.File, l.Line, l.Reason, l.Link)
+}
+
+// CheckLinks checks all relative links in the Markdown files in the given directory.
+// Links must point to existing files, and anchors to existing headings or HTML anchors.
+// For format MyST, {doc} roles are checked, too.
+// Absolute URLs and site-absolute paths are not checked.
+// Returns an error for formats that don't produce Markdown, and if there are no Markdown files.
+func CheckLinks(dir string, f document.Formatter) ([]*BrokenLink, error) {
+	switch f.(type) {
+	case *HTML:
+		return nil, fmt.Errorf("link checking is not supported for format 'html'")
+	case *Man:
+		return nil, fmt.Errorf("link checking is not supported for format 'man'")
+	case *Plugin:
+		return nil, fmt.Errorf("link checking is not supported for formatter plugins")
+	}
+	_, docRoles := f.(*MyST)
+	slug := headingSlug
+	maxLevel := 6
+	if docRoles {
+		maxLevel = mystHeadingAnchors
+	}
+	if s, ok := f.(document.Slugger); ok {
+		slug = s.Slug
+	}
+	files := []string{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isMarkdownFile(p) {
+			files = append(files, filepath.ToSlash(p))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Markdown files found to check in %s", dir)
+	}
+	sort.Strings(files)
+
+	anchors := map[string]map[string]bool{}
+	broken := []*BrokenLink{}
+	for _, file := range files {
+		links, err := findFileLinks(file, docRoles)
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range links {
+			reason, err := checkLink(file, link.Link, filepath.ToSlash(dir), slug, maxLevel, anchors)
+			if err != nil {
+				return nil, err
+			}
+			if reason != "" {
+				link.Reason = reason
+				broken = append(broken, link)
+			}
+		}
+	}
+	return broken, nil
+}
+
+// Checks a single link. Returns the reason if it is broken, or an empty string otherwise.
+// Only headings up to maxLevel create anchors.
+func checkLink(file, link, root string, slug func(string) string, maxLevel int, anchors map[string]map[string]bool) (string, error) {
+	if strings.HasPrefix(link, "/") || strings.HasPrefix(link, "{{") && !checkHugoRefRegex.MatchString(link) {
+		return "", nil
+	}
+	bases := []string{path.Dir(file)}
+	if m := checkHugoRefRegex.FindStringSubmatch(link); m != nil {
+		// Hugo resolves refs relative to the page, and then relative to the content root.
+		link = m[1] + m[2]
+		bases = append(bases, root)
+	} else if strings.HasPrefix(link, zolaLinkPrefix) {
+		link = strings.TrimPrefix(link, zolaLinkPrefix)
+		bases = []string{root}
+	} else if checkSchemeRegex.MatchString(link) {
+		return "", nil
+	}
+
+	target, anchor, _ := strings.Cut(link, "#")
+	target, _, _ = strings.Cut(target, "?")
+	targetFile := file
+	if target != "" {
+		found := false
+		for _, base := range bases {
+			var err error
+			if targetFile, found, err = resolveLinkTarget(base, target); err != nil {
+				return "", err
+			}
+			if found {
+				break
+			}
+		}
+		if !found {
+			return "file not found", nil
+		}
+	}
+	if anchor == "" || !isMarkdownFile(targetFile) {
+		return "", nil
+	}
+
+	fileAnchors, ok := anchors[targetFile]
+	if !ok {
+		var err error
+		if fileAnchors, err = findFileAnchors(targetFile, slug, maxLevel); err != nil {
+			return "", err
+		}
+		anchors[targetFile] = fileAnchors
+	}
+	if !fileAnchors[anchor] {
+		return "anchor not found", nil
+	}
+	return "", nil
+}
+
+// Resolves a link target to an existing file.
+// Directories resolve to their index file if there is one.
+// Targets without extension are also tried with suffix '.md', as used by wikis.
+func resolveLinkTarget(base, target string) (string, bool, error) {
+	file := path.Join(base, target)
+	candidates := []string{file}
+	if path.Ext(file) == "" {
+		candidates = append(candidates, file+".md")
+	}
+	for _, c := range candidates {
+		info, err := os.Stat(c)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", false, err
+		}
+		if !info.IsDir() {
+			return c, true, nil
+		}
+		for _, index := range []string{"_index.md", "index.md", "index.mdx", "README.md"} {
+			if _, err := os.Stat(path.Join(c, index)); err == nil {
+				return path.Join(c, index), true, nil
+			}
+		}
+		return c, true, nil
+	}
+	return "", false, nil
+}
+
+// Finds all links in a Markdown file, outside of code.
+// With docRoles, the targets of MyST {doc} roles are included.
+func findFileLinks(file string, docRoles bool) ([]*BrokenLink, error) {
+	links := []*BrokenLink{}
+	err := scanMarkdown(file, func(line string, lineNum int) {
+		if docRoles {
+			for _, m := range checkDocRoleRegex.FindAllStringSubmatch(line, -1) {
+				links = append(links, &BrokenLink{File: file, Line: lineNum, Link: strings.TrimSpace(m[1] + m[2])})
+			}
+		}
+		line = checkInlineCodeRegex.ReplaceAllString(line, "")
+		if m := checkRefDefRegex.FindStringSubmatch(line); m != nil {
+			links = append(links, &BrokenLink{File: file, Line: lineNum, Link: strings.Trim(m[1], "<>")})
+			return
+		}
+		for _, m := range checkLinkRegex.FindAllStringSubmatch(line, -1) {
+			links = append(links, &BrokenLink{File: file, Line: lineNum, Link: strings.Trim(m[1], "<>")})
+		}
+	})
+	return links, err
+}
+
+// Finds all anchors in a Markdown file, from headings and HTML anchors.
+// Repeated headings get numbered suffixes, like on GitHub.
+// Headings deeper than maxLevel are ignored, except for explicit IDs.
+func findFileAnchors(file string, slug func(string) string, maxLevel int) (map[string]bool, error) {
+	anchors := map[string]bool{}
+	counts := map[string]int{}
+	err := scanMarkdown(file, func(line string, lineNum int) {
+		for _, m := range checkHTMLAnchorRegex.FindAllStringSubmatch(line, -1) {
+			anchors[m[1]] = true
+		}
+		m := mdHeadingRegex.FindStringSubmatch(line)
+		if m == nil {
+			return
+		}
+		heading := m[2]
+		if id := checkHeadingIDRegex.FindStringSubmatch(heading); id != nil {
+			anchors[id[1]] = true
+			return
+		}
+		if len(m[1]) > maxLevel {
+			return
+		}
+		s := slug(heading)
+		if n := counts[s]; n > 0 {
+			anchors[fmt.Sprintf("%s-%d", s, n)] = true
+		} else {
+			anchors[s] = true
+		}
+		counts[s]++
+	})
+	return anchors, err
+}
+
+// Calls the given function for each line of a Markdown file,
+// skipping front matter and fenced code blocks.
+func scanMarkdown(file string, fn func(line string, lineNum int)) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	lineNum := 0
+	fence := ""
+	frontMatter := ""
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNum++
+		trimmed := strings.TrimSpace(line)
+		if lineNum == 1 && (trimmed == "---" || trimmed == "+++") {
+			frontMatter = trimmed
+			continue
+		}
+		if frontMatter != "" {
+			if trimmed == frontMatter {
+				frontMatter = ""
+			}
+			continue
+		}
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		fn(line, lineNum)
+	}
+	return scanner.Err()
+}
+
+func isMarkdownFile(file string) bool {
+	switch strings.ToLower(path.Ext(file)) {
+	case ".md", ".mdx", ".markdown":
+		return true
+	}
+	return false
+}
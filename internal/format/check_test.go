@@ -0,0 +1,114 @@
+package format
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckLinks(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"_index.md": `---
+title: "[front matter](missing.md)"
+---
+# Index
+
+See [Struct](mod/Struct.md#method), [Struct](mod/Struct.md#missing) and [missing](mod/Missing.md).
+Also [mod](mod/), [mod](mod/_index.md#functions), [section](#index) and [external](https://example.com/missing.md).
+
+` + "```mojo\n[not a link](missing.md)\n```\n\n`[code](missing.md)`\n\n[ref]: mod/Missing.md\n",
+		"mod/_index.md": "# mod\n\n## Functions\n\n## Functions\n\nSee [second](#functions-1) and [third](#functions-2).\n",
+		"mod/Struct.md": "# Struct\n\n### `method`\n\n<a id=\"custom\"></a>\n\nSee [custom](#custom) and [hugo]({{< ref \"mod/_index.md\" >}}#functions).\n",
+	}
+	for name, content := range files {
+		file := path.Join(dir, name)
+		assert.Nil(t, os.MkdirAll(path.Dir(file), 0755))
+		assert.Nil(t, os.WriteFile(file, []byte(content), 0644))
+	}
+
+	broken, err := CheckLinks(dir, &Plain{})
+	assert.Nil(t, err)
+
+	result := []string{}
+	for _, b := range broken {
+		result = append(result, b.String())
+	}
+	assert.Equal(t, []string{
+		dir + "/_index.md:6: anchor not found: mod/Struct.md#missing",
+		dir + "/_index.md:6: file not found: mod/Missing.md",
+		dir + "/_index.md:15: file not found: mod/Missing.md",
+		dir + "/mod/_index.md:7: anchor not found: #functions-2",
+	}, result)
+}
+
+func TestCheckLinksZola(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(path.Join(dir, "mod"), 0755))
+	assert.Nil(t, os.WriteFile(path.Join(dir, "mod", "Struct.md"), []byte("# Struct\n\n### `__init__`\n"), 0644))
+	assert.Nil(t, os.WriteFile(path.Join(dir, "_index.md"), []byte("See [init](@/mod/Struct.md#init) and [bad](@/mod/Struct.md#__init__).\n"), 0644))
+
+	broken, err := CheckLinks(dir, &Zola{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(broken))
+	assert.Equal(t, "@/mod/Struct.md#__init__", broken[0].Link)
+}
+
+func TestCheckLinksMyST(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(path.Join(dir, "mod"), 0755))
+	assert.Nil(t, os.WriteFile(path.Join(dir, "mod", "Struct.md"), []byte("# Struct\n"), 0644))
+	assert.Nil(t, os.WriteFile(path.Join(dir, "index.md"), []byte(
+		"See {doc}`Struct <mod/Struct>`, {doc}`a \\<b> <mod/Struct.md>`, {doc}`mod/Missing` and {doc}`bad <mod/Other>`.\n",
+	), 0644))
+
+	broken, err := CheckLinks(dir, &MyST{})
+	assert.Nil(t, err)
+	result := []string{}
+	for _, b := range broken {
+		result = append(result, b.Link)
+	}
+	assert.Equal(t, []string{"mod/Missing", "mod/Other"}, result)
+
+	broken, err = CheckLinks(dir, &Plain{})
+	assert.Nil(t, err)
+	assert.Empty(t, broken)
+}
+
+func TestCheckLinksMySTHeadingLevels(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(path.Join(dir, "Struct.md"), []byte(
+		"# Struct\n\n### `f`\n\n#### `f` (2)\n\n##### Deep\n",
+	), 0644))
+	assert.Nil(t, os.WriteFile(path.Join(dir, "index.md"), []byte(
+		"See [f](Struct.md#f), [f(2)](Struct.md#f-2) and [deep](Struct.md#deep).\n",
+	), 0644))
+
+	broken, err := CheckLinks(dir, &MyST{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(broken))
+	assert.Equal(t, "Struct.md#deep", broken[0].Link)
+
+	broken, err = CheckLinks(dir, &Plain{})
+	assert.Nil(t, err)
+	assert.Empty(t, broken)
+}
+
+func TestCheckLinksUnsupported(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(path.Join(dir, "index.html"), []byte("<a href=\"missing.html\">missing</a>\n"), 0644))
+
+	_, err := CheckLinks(dir, &HTML{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "not supported for format 'html'")
+
+	_, err = CheckLinks(dir, &Man{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "not supported for format 'man'")
+
+	_, err = CheckLinks(dir, &Plain{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "no Markdown files found")
+}
@@ -0,0 +1,423 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/mlange-42/modo/internal/util"
+)
+
+const landingPageContentDocusaurus = `---
+slug: /
+sidebar_position: 1
+---
+
+# Landing page
+
+JSON created by mojo doc should be placed next to this file.
+
+Additional documentation files go here, too.
+They will be processed for doc-tests and copied to folder 'site/docs'.
+`
+
+const docusaurusCategoryFile = "_category_.json"
+
+var mdxAutolinkRegex = regexp.MustCompile(`^<(https?://[^\s<>]+)>`)
+var mdxTagRegex = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9]*)(\s[^<>]*?)?(/?)>`)
+
+// mdxHTMLTags are the HTML tags that are kept in MDX output. Any other '<' is escaped.
+var mdxHTMLTags = map[string]bool{
+	"a": true, "abbr": true, "b": true, "blockquote": true, "br": true, "code": true, "dd": true,
+	"del": true, "details": true, "div": true, "dl": true, "dt": true, "em": true, "h1": true,
+	"h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "hr": true, "i": true, "img": true,
+	"ins": true, "kbd": true, "li": true, "mark": true, "ol": true, "p": true, "pre": true, "s": true,
+	"small": true, "span": true, "strong": true, "sub": true, "summary": true, "sup": true, "table": true,
+	"tbody": true, "td": true, "th": true, "thead": true, "tr": true, "u": true, "ul": true, "wbr": true,
+}
+
+// mdxVoidTags are HTML tags without content, that need to be self-closing in MDX.
+var mdxVoidTags = map[string]bool{"br": true, "hr": true, "img": true, "wbr": true}
+
+var mdxAttrRegex = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)(?:\s*=\s*("[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?`)
+var mdxPlaceholderRegex = regexp.MustCompile("\x00([0-9]+)\x00")
+
+// mdxAttrNames maps HTML attribute names to their JSX equivalents.
+var mdxAttrNames = map[string]string{"class": "className", "for": "htmlFor"}
+
+// mdxTag is an HTML tag kept in MDX output, if it is balanced.
+type mdxTag struct {
+	Name    string
+	Closing bool
+	Raw     string
+	JSX     string
+	Matched bool
+}
+
+type Docusaurus struct{}
+
+type docusaurusConfig struct {
+	Title   string
+	Name    string
+	URL     string
+	BaseURL string
+	Repo    string
+}
+
+type docusaurusCategory struct {
+	Label    string `json:"label"`
+	Position int    `json:"position"`
+}
+
+func (f *Docusaurus) Accepts(files []string) error {
+	return nil
+}
+
+func (f *Docusaurus) ProcessMarkdown(element any, text string, proc *document.Processor) (string, error) {
+	b := strings.Builder{}
+	err := proc.Template.ExecuteTemplate(&b, "docusaurus_front_matter.md", element)
+	if err != nil {
+		return "", err
+	}
+	b.WriteRune('\n')
+	b.WriteString(escapeMDX(text))
+	return b.String(), nil
+}
+
+func (f *Docusaurus) WriteAuxiliary(p *document.Package, dir string, proc *document.Processor) error {
+	return f.writeCategoriesPackage(p, dir, proc)
+}
+
+func (f *Docusaurus) ToFilePath(p string, kind string) string {
+	if kind == "package" || kind == "module" {
+		return path.Join(p, "index.mdx")
+	}
+	if len(p) == 0 {
+		return p
+	}
+	return p + ".mdx"
+}
+
+func (f *Docusaurus) ToLinkPath(p string, kind string) string {
+	return f.ToFilePath(p, kind)
+}
+
+// ToURL converts a member path to the URL of its page.
+func (f *Docusaurus) ToURL(p string, kind string) string {
+	if kind == "package" || kind == "module" {
+		return p + "/"
+	}
+	return p
+}
+
+func (f *Docusaurus) Input(in string, sources []document.PackageSource) string {
+	return in
+}
+
+func (f *Docusaurus) Output(out string) string {
+	return path.Join(out, "docs")
+}
+
+func (f *Docusaurus) GitIgnore(in, out string, sources []document.PackageSource) []string {
+	return []string{
+		"# files generated by 'mojo doc'",
+		fmt.Sprintf("/%s/*.json", in),
+		"# files generated by Modo",
+		fmt.Sprintf("/%s/%s/", out, "docs"),
+		"# files generated by Docusaurus",
+		fmt.Sprintf("/%s/%s/", out, "build"),
+		fmt.Sprintf("/%s/%s/", out, ".docusaurus"),
+		fmt.Sprintf("/%s/%s/", out, "node_modules"),
+		"# test file generated by Modo",
+		"/test/",
+	}
+}
+
+func (f *Docusaurus) CreateDirs(base, in, out string, sources []document.PackageSource, templ *template.Template) error {
+	inDir, outDir := path.Join(base, in), path.Join(base, f.Output(out))
+	testDir := path.Join(base, "test")
+	if err := util.MkDirs(inDir); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path.Join(inDir, "index.md"), []byte(landingPageContentDocusaurus), 0644); err != nil {
+		return err
+	}
+	if err := util.MkDirs(outDir); err != nil {
+		return err
+	}
+	if err := util.MkDirs(testDir); err != nil {
+		return err
+	}
+	return f.createInitialFiles(base, path.Join(base, out), templ)
+}
+
+func (f *Docusaurus) createInitialFiles(docDir, siteDir string, templ *template.Template) error {
+	gitInfo, err := document.GetGitOrigin(docDir)
+	if err != nil {
+		return err
+	}
+	config := docusaurusConfig{
+		Title:   gitInfo.Title,
+		Name:    strings.ToLower(gitInfo.Title),
+		URL:     gitInfo.Pages,
+		BaseURL: "/",
+		Repo:    gitInfo.Repo,
+	}
+	if u, err := url.Parse(gitInfo.Pages); err == nil && u.Host != "" {
+		config.URL = fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+		if u.Path != "" {
+			config.BaseURL = u.Path
+		}
+	}
+
+	files := [][]string{
+		{"docusaurus.config.js", "docusaurus.config.js"},
+		{"docusaurus.sidebars.js", "sidebars.js"},
+		{"docusaurus.package.json", "package.json"},
+	}
+	for _, f := range files {
+		outFile := path.Join(siteDir, f[1])
+		exists, _, err := util.FileExists(outFile)
+		if err != nil {
+			return err
+		}
+		if exists {
+			fmt.Printf("WARNING: Docusaurus file %s already exists, skip creating\n", outFile)
+			return nil
+		}
+	}
+
+	for _, f := range files {
+		outFile := path.Join(siteDir, f[1])
+		b := bytes.Buffer{}
+		if err := templ.ExecuteTemplate(&b, f[0], &config); err != nil {
+			return err
+		}
+		if err := os.WriteFile(outFile, b.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Docusaurus) Clean(out, tests string) error {
+	if err := emptyDir(out); err != nil {
+		return err
+	}
+	return document.CleanDocTests(tests)
+}
+
+func (f *Docusaurus) writeCategoriesPackage(p *document.Package, dir string, proc *document.Processor) error {
+	pkgDir := path.Join(dir, p.GetFileName())
+	if err := f.writeCategory(p.GetName(), 400, pkgDir, proc); err != nil {
+		return err
+	}
+	for _, pkg := range p.Packages {
+		if err := f.writeCategoriesPackage(pkg, pkgDir, proc); err != nil {
+			return err
+		}
+	}
+	for _, mod := range p.Modules {
+		if err := f.writeCategory(mod.GetName(), 300, path.Join(pkgDir, mod.GetFileName()), proc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Docusaurus) writeCategory(label string, position int, dir string, proc *document.Processor) error {
+	if proc.Config.DryRun {
+		return nil
+	}
+	content, err := json.MarshalIndent(&docusaurusCategory{Label: label, Position: position}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path.Join(dir, docusaurusCategoryFile), append(content, '\n'), 0644)
+}
+
+// escapeMDX escapes characters that have a special meaning in MDX,
+// except in code blocks and inline code.
+// Known HTML tags are kept if they are balanced on the page, with attributes converted to JSX.
+func escapeMDX(text string) string {
+	b := strings.Builder{}
+	tags := []*mdxTag{}
+	lines := strings.Split(text, "\n")
+	fence := ""
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteRune('\n')
+		}
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			b.WriteString(line)
+			continue
+		}
+		if marker := fenceMarker(trimmed); marker != "" {
+			fence = marker
+			b.WriteString(line)
+			continue
+		}
+		tags = escapeMDXLine(line, &b, tags)
+	}
+	if len(tags) == 0 {
+		return b.String()
+	}
+
+	matchMDXTags(tags)
+	return mdxPlaceholderRegex.ReplaceAllStringFunc(b.String(), func(s string) string {
+		idx, _ := strconv.Atoi(s[1 : len(s)-1])
+		tag := tags[idx]
+		if tag.Matched {
+			return tag.JSX
+		}
+		return "&lt;" + strings.NewReplacer("{", `\{`, "}", `\}`).Replace(tag.Raw[1:])
+	})
+}
+
+// matchMDXTags marks opening and closing tags that match each other.
+func matchMDXTags(tags []*mdxTag) {
+	open := []*mdxTag{}
+	for _, tag := range tags {
+		if !tag.Closing {
+			open = append(open, tag)
+			continue
+		}
+		for i := len(open) - 1; i >= 0; i-- {
+			if open[i].Name == tag.Name {
+				open[i].Matched = true
+				tag.Matched = true
+				open = open[:i]
+				break
+			}
+		}
+	}
+}
+
+// mdxAttributes converts HTML attributes to JSX attributes.
+// String 'style' attributes are dropped, as JSX requires an object.
+func mdxAttributes(attrs string) string {
+	b := strings.Builder{}
+	for _, m := range mdxAttrRegex.FindAllStringSubmatch(attrs, -1) {
+		name := strings.ToLower(m[1])
+		if name == "style" {
+			continue
+		}
+		if jsxName, ok := mdxAttrNames[name]; ok {
+			name = jsxName
+		} else {
+			name = m[1]
+		}
+		value := m[2]
+		if strings.HasPrefix(value, "'") {
+			value = `"` + strings.ReplaceAll(value[1:len(value)-1], `"`, "&quot;") + `"`
+		} else if value != "" && !strings.HasPrefix(value, `"`) {
+			value = `"` + value + `"`
+		}
+		b.WriteRune(' ')
+		b.WriteString(name)
+		if value != "" {
+			b.WriteRune('=')
+			b.WriteString(value)
+		}
+	}
+	return b.String()
+}
+
+func escapeMDXLine(line string, b *strings.Builder, tags []*mdxTag) []*mdxTag {
+	for i := 0; i < len(line); {
+		c := line[i]
+		if c == '`' {
+			n := runLength(line[i:], '`')
+			if end := findRun(line[i+n:], '`', n); end >= 0 {
+				b.WriteString(line[i : i+n+end+n])
+				i += n + end + n
+				continue
+			}
+			b.WriteString(line[i : i+n])
+			i += n
+			continue
+		}
+		switch c {
+		case '{':
+			b.WriteString(`\{`)
+		case '}':
+			b.WriteString(`\}`)
+		case '<':
+			if m := mdxAutolinkRegex.FindStringSubmatch(line[i:]); m != nil {
+				// MDX doesn't support autolinks, so they are converted to normal links.
+				fmt.Fprintf(b, "[%s](%s)", m[1], m[1])
+				i += len(m[0])
+				continue
+			}
+			if m := mdxTagRegex.FindStringSubmatch(line[i:]); m != nil && mdxHTMLTags[strings.ToLower(m[2])] {
+				name := strings.ToLower(m[2])
+				i += len(m[0])
+				if m[1] != "" {
+					tags = append(tags, &mdxTag{Name: name, Closing: true, Raw: m[0], JSX: "</" + m[2] + ">"})
+					fmt.Fprintf(b, "\x00%d\x00", len(tags)-1)
+					continue
+				}
+				jsx := "<" + m[2] + mdxAttributes(m[3])
+				if m[4] != "" || mdxVoidTags[name] {
+					// JSX requires void elements to be closed.
+					b.WriteString(jsx + " />")
+					continue
+				}
+				tags = append(tags, &mdxTag{Name: name, Raw: m[0], JSX: jsx + ">"})
+				fmt.Fprintf(b, "\x00%d\x00", len(tags)-1)
+				continue
+			}
+			b.WriteString("&lt;")
+		default:
+			b.WriteByte(c)
+		}
+		i++
+	}
+	return tags
+}
+
+// fenceMarker returns the opening code fence of a line, or an empty string.
+func fenceMarker(line string) string {
+	for _, c := range []byte{'`', '~'} {
+		if n := runLength(line, c); n >= 3 {
+			return line[:n]
+		}
+	}
+	return ""
+}
+
+func runLength(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+// findRun finds the start of a run of exactly n characters c.
+func findRun(s string, c byte, n int) int {
+	for i := 0; i < len(s); {
+		if s[i] != c {
+			i++
+			continue
+		}
+		l := runLength(s[i:], c)
+		if l == n {
+			return i
+		}
+		i += l
+	}
+	return -1
+}
@@ -0,0 +1,146 @@
+package format
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocusaurusToFilePath(t *testing.T) {
+	f := Docusaurus{}
+
+	text := f.ToFilePath("pkg/mod/Struct", "struct")
+	assert.Equal(t, text, "pkg/mod/Struct.mdx")
+
+	text = f.ToFilePath("pkg/mod", "module")
+	assert.Equal(t, text, "pkg/mod/index.mdx")
+
+	text = f.ToFilePath("pkg", "package")
+	assert.Equal(t, text, "pkg/index.mdx")
+}
+
+func TestDocusaurusToLinkPath(t *testing.T) {
+	f := Docusaurus{}
+
+	text := f.ToLinkPath("pkg/mod/Struct", "struct")
+	assert.Equal(t, text, "pkg/mod/Struct.mdx")
+
+	text = f.ToLinkPath("pkg", "package")
+	assert.Equal(t, text, "pkg/index.mdx")
+}
+
+func TestDocusaurusProcessMarkdown(t *testing.T) {
+	form := Docusaurus{}
+	templ, err := document.LoadTemplates(&form, "")
+	assert.Nil(t, err)
+
+	proc := document.NewProcessor(nil, &form, templ, &document.Config{})
+
+	text, err := form.ProcessMarkdown(&document.Struct{
+		MemberName: document.MemberName{Name: "Struct"},
+		MemberKind: document.MemberKind{Kind: "struct"},
+	}, "A {set} of <T>, `{code}` and ``a `{b}` c``\n```mojo\nfn f[T: A = {}]()\n```\n{x}", proc)
+	assert.Nil(t, err)
+
+	assert.Equal(t,
+		strings.ReplaceAll(text, "\r\n", "\n"),
+		"---\n"+
+			"id: Struct\n"+
+			"title: Struct\n"+
+			"sidebar_position: 100\n"+
+			"---\n"+
+			"\n"+
+			"A \\{set\\} of &lt;T>, `{code}` and ``a `{b}` c``\n"+
+			"```mojo\n"+
+			"fn f[T: A = {}]()\n"+
+			"```\n"+
+			"\\{x\\}")
+}
+
+func TestEscapeMDX(t *testing.T) {
+	tests := []struct {
+		text, expected string
+	}{
+		{"A <T> and a < b", "A &lt;T> and a &lt; b"},
+		{"See <https://example.com/a?b=c>.", "See [https://example.com/a?b=c](https://example.com/a?b=c)."},
+		{"No <ftp://example.com> autolink", "No &lt;ftp://example.com> autolink"},
+		{"Some <b>bold</b> and <span class=\"x\">span</span>", "Some <b>bold</b> and <span className=\"x\">span</span>"},
+		{"Line<br>break, <br/> and <img src=\"a.png\">", "Line<br />break, <br /> and <img src=\"a.png\" />"},
+		{"A <List> or <Dict[K, V]>", "A &lt;List> or &lt;Dict[K, V]>"},
+		{"<div class='note' style=\"color:red\" id=x>text</div>", "<div className=\"note\" id=\"x\">text</div>"},
+		{"<td colspan=2 align=\"right\">x</td>", "<td colspan=\"2\" align=\"right\">x</td>"},
+		{"An unclosed <p> and <b>bold", "An unclosed &lt;p> and &lt;b>bold"},
+		{"A stray </b> tag", "A stray &lt;/b> tag"},
+		{"<b>Bold <i>unclosed</b>", "<b>Bold &lt;i>unclosed</b>"},
+		{"<span title=\"{x}\">a", "&lt;span title=\"\\{x\\}\">a"},
+		{"<details open>\n<summary>More</summary>\n\nText {x}\n</details>", "<details open>\n<summary>More</summary>\n\nText \\{x\\}\n</details>"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, escapeMDX(tt.text), "Text: %q", tt.text)
+	}
+}
+
+func TestDocusaurusOutput(t *testing.T) {
+	f := Docusaurus{}
+	assert.Equal(t, f.Output("site"), "site/docs")
+}
+
+func TestDocusaurusGitIgnore(t *testing.T) {
+	f := Docusaurus{}
+	gi := f.GitIgnore("src", "site", []document.PackageSource{})
+
+	assert.Contains(t, gi, "/src/*.json")
+	assert.Contains(t, gi, "/site/docs/")
+	assert.Contains(t, gi, "/site/build/")
+	assert.Contains(t, gi, "/site/node_modules/")
+	assert.Contains(t, gi, "/test/")
+}
+
+func TestDocusaurusCreateDirs(t *testing.T) {
+	f := Docusaurus{}
+	testCreateDirs(&f, t, "docs/site/docs", []string{
+		".",
+		"docs",
+		"docs/site",
+		"docs/site/docs",
+		"docs/site/docusaurus.config.js",
+		"docs/site/package.json",
+		"docs/site/sidebars.js",
+		"docs/src",
+		"docs/src/index.md",
+		"docs/test",
+	})
+}
+
+func TestDocusaurusWriteAuxiliary(t *testing.T) {
+	f := Docusaurus{}
+	pkg := document.Package{
+		MemberName: document.MemberName{Name: "pkg"},
+		MemberKind: document.MemberKind{Kind: "package"},
+		Modules: []*document.Module{
+			{
+				MemberName: document.MemberName{Name: "mod"},
+				MemberKind: document.MemberKind{Kind: "module"},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	err := os.MkdirAll(path.Join(dir, "pkg", "mod"), os.ModePerm)
+	assert.Nil(t, err)
+
+	proc := document.NewProcessor(nil, &f, nil, &document.Config{OutputDir: dir})
+	err = f.WriteAuxiliary(&pkg, dir, proc)
+	assert.Nil(t, err)
+
+	content, err := os.ReadFile(path.Join(dir, "pkg", "mod", "_category_.json"))
+	assert.Nil(t, err)
+	assert.Equal(t, "{\n  \"label\": \"mod\",\n  \"position\": 300\n}\n", string(content))
+
+	_, err = os.Stat(path.Join(dir, "pkg", "_category_.json"))
+	assert.Nil(t, err)
+}
@@ -2,21 +2,60 @@ package format
 
 import (
 	"fmt"
+	"io"
+	"os/exec"
+	"strings"
 
 	"github.com/mlange-42/modo/internal/document"
 )
 
+// pluginPrefix marks a format as the path of a formatter plugin executable.
+const pluginPrefix = "plugin:"
+
 var formats = map[string]document.Formatter{
-	"":       &Plain{},
-	"plain":  &Plain{},
-	"mdbook": &MdBook{},
-	"hugo":   &Hugo{},
+	"":           &Plain{},
+	"plain":      &Plain{},
+	"mdbook":     &MdBook{},
+	"hugo":       &Hugo{},
+	"mkdocs":     &MkDocs{},
+	"docusaurus": &Docusaurus{},
+	"zola":       &Zola{},
+	"html":       &HTML{},
+	"myst":       &MyST{},
+	"wiki":       &Wiki{},
+	"man":        &Man{},
 }
 
+// Formatter plugins, by executable. Plugins are re-used to keep their processes running.
+var plugins = map[string]*Plugin{}
+
+// GetFormatter returns the formatter for the given format.
+// Formats prefixed with 'plugin:' are looked up as formatter plugin executable.
 func GetFormatter(f string) (document.Formatter, error) {
 	fm, ok := formats[f]
-	if !ok {
+	if ok {
+		return fm, nil
+	}
+	if !strings.HasPrefix(f, pluginPrefix) {
 		return nil, fmt.Errorf("unknown format '%s'. See flag --format", f)
 	}
-	return fm, nil
+	if plugin, ok := plugins[f]; ok {
+		return plugin, nil
+	}
+	executable := strings.TrimPrefix(f, pluginPrefix)
+	command, err := exec.LookPath(executable)
+	if err != nil {
+		return nil, fmt.Errorf("no executable found for formatter plugin '%s': %s", executable, err.Error())
+	}
+	plugin := newPlugin(command)
+	plugins[f] = plugin
+	return plugin, nil
+}
+
+// Close shuts down the formatter at the end of a build, if it runs an external process.
+func Close(f document.Formatter) error {
+	if c, ok := f.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
 }
@@ -1,6 +1,7 @@
 package format_test
 
 import (
+	"os"
 	"testing"
 
 	"github.com/mlange-42/modo/internal/format"
@@ -16,6 +17,34 @@ func TestGetFormatter(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Empty(t, f, &format.MdBook{})
 
+	f, err = format.GetFormatter("mkdocs")
+	assert.Nil(t, err)
+	assert.Empty(t, f, &format.MkDocs{})
+
+	f, err = format.GetFormatter("docusaurus")
+	assert.Nil(t, err)
+	assert.Empty(t, f, &format.Docusaurus{})
+
+	f, err = format.GetFormatter("zola")
+	assert.Nil(t, err)
+	assert.Empty(t, f, &format.Zola{})
+
+	f, err = format.GetFormatter("html")
+	assert.Nil(t, err)
+	assert.Empty(t, f, &format.HTML{})
+
+	f, err = format.GetFormatter("myst")
+	assert.Nil(t, err)
+	assert.Empty(t, f, &format.MyST{})
+
+	f, err = format.GetFormatter("wiki")
+	assert.Nil(t, err)
+	assert.Empty(t, f, &format.Wiki{})
+
+	f, err = format.GetFormatter("man")
+	assert.Nil(t, err)
+	assert.Empty(t, f, &format.Man{})
+
 	f, err = format.GetFormatter("plain")
 	assert.Nil(t, err)
 	assert.Empty(t, f, &format.Plain{})
@@ -23,4 +52,17 @@ func TestGetFormatter(t *testing.T) {
 	f, err = format.GetFormatter("foobar")
 	assert.NotNil(t, err)
 	assert.Nil(t, f)
+
+	// Executables are only used as plugins with an explicit prefix.
+	f, err = format.GetFormatter(os.Args[0])
+	assert.NotNil(t, err)
+	assert.Nil(t, f)
+
+	f, err = format.GetFormatter("plugin:" + os.Args[0])
+	assert.Nil(t, err)
+	assert.IsType(t, &format.Plugin{}, f)
+
+	f, err = format.GetFormatter("plugin:modo-plugin-that-does-not-exist")
+	assert.NotNil(t, err)
+	assert.Nil(t, f)
 }
@@ -0,0 +1,211 @@
+package format
+
+import (
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/mlange-42/modo/assets"
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/mlange-42/modo/internal/util"
+)
+
+const landingPageContentHTML = `# Landing page
+
+JSON created by mojo doc should be placed next to this file.
+
+Additional documentation files go here, too.
+They will be processed for doc-tests and converted to HTML pages in folder 'site'.
+This file becomes the site's 'index.html'.
+`
+
+type HTML struct{}
+
+type htmlPage struct {
+	Title   string
+	CSS     string
+	Nav     string
+	Content string
+}
+
+func (f *HTML) Accepts(files []string) error {
+	return nil
+}
+
+func (f *HTML) ProcessMarkdown(element any, text string, proc *document.Processor) (string, error) {
+	return text, nil
+}
+
+// ProcessPage converts the Markdown of a page to HTML,
+// and wraps it into the page layout with a navigation sidebar.
+func (f *HTML) ProcessPage(text string, dir string, proc *document.Processor) (string, error) {
+	return f.renderPage(text, dir, proc, []*document.Processor{proc})
+}
+
+// ConvertPage converts a handwritten Markdown page to an HTML page, with a navigation sidebar for all packages.
+// Pages 'index.md' and '_index.md' become 'index.html'.
+// Relative links to other Markdown pages are changed to point to the HTML pages.
+func (f *HTML) ConvertPage(text string, file string, dir string, proc *document.Processor, procs []*document.Processor) (string, string, error) {
+	base := strings.TrimSuffix(file, filepath.Ext(file))
+	if name := filepath.Base(base); name == "index" || name == "_index" {
+		base = filepath.Join(filepath.Dir(base), "index")
+	}
+	text = mdPageLinkRegex.ReplaceAllStringFunc(text, func(link string) string {
+		parts := mdPageLinkRegex.FindStringSubmatch(link)
+		target := parts[1]
+		if name := path.Base(target); name == "index" || name == "_index" {
+			target = path.Join(path.Dir(target), "index")
+		}
+		return fmt.Sprintf("](%s.html%s)", target, parts[2])
+	})
+	content, err := f.renderPage(text, dir, proc, procs)
+	if err != nil {
+		return "", "", err
+	}
+	return content, base + ".html", nil
+}
+
+func (f *HTML) renderPage(text string, dir string, proc *document.Processor, navProcs []*document.Processor) (string, error) {
+	css, err := fs.ReadFile(assets.CSS, "css/html.css")
+	if err != nil {
+		return "", err
+	}
+	nav := strings.Builder{}
+	for _, p := range navProcs {
+		if p.ExportDocs != nil {
+			f.renderNavPackage(p.ExportDocs.Decl, p.PackageDir(), dir, &nav)
+		}
+	}
+	page := htmlPage{
+		Title:   html.EscapeString(pageTitle(text)),
+		CSS:     string(css),
+		Nav:     nav.String(),
+		Content: markdownToHTML(text),
+	}
+
+	b := strings.Builder{}
+	if err := proc.Template.ExecuteTemplate(&b, "html_page.html", &page); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func (f *HTML) WriteAuxiliary(p *document.Package, dir string, proc *document.Processor) error {
+	return nil
+}
+
+func (f *HTML) ToFilePath(p string, kind string) string {
+	if kind == "package" || kind == "module" {
+		return path.Join(p, "index.html")
+	}
+	if len(p) == 0 {
+		return p
+	}
+	return p + ".html"
+}
+
+func (f *HTML) ToLinkPath(p string, kind string) string {
+	return f.ToFilePath(p, kind)
+}
+
+func (f *HTML) Input(in string, sources []document.PackageSource) string {
+	return in
+}
+
+func (f *HTML) Output(out string) string {
+	return out
+}
+
+func (f *HTML) GitIgnore(in, out string, sources []document.PackageSource) []string {
+	return []string{
+		"# files generated by 'mojo doc'",
+		fmt.Sprintf("/%s/*.json", in),
+		"# files generated by Modo",
+		fmt.Sprintf("/%s/", out),
+		"# test file generated by Modo",
+		"/test/",
+	}
+}
+
+func (f *HTML) CreateDirs(base, in, out string, sources []document.PackageSource, _ *template.Template) error {
+	inDir, outDir := path.Join(base, in), path.Join(base, out)
+	testDir := path.Join(base, "test")
+	if err := util.MkDirs(inDir); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path.Join(inDir, "_index.md"), []byte(landingPageContentHTML), 0644); err != nil {
+		return err
+	}
+	if err := util.MkDirs(outDir); err != nil {
+		return err
+	}
+	return util.MkDirs(testDir)
+}
+
+func (f *HTML) Clean(out, tests string) error {
+	if err := emptyDir(out); err != nil {
+		return err
+	}
+	return document.CleanDocTests(tests)
+}
+
+func (f *HTML) renderNavPackage(p *document.Package, pkgDir, pageDir string, b *strings.Builder) {
+	f.writeNavLink(p.GetName(), f.ToLinkPath(pkgDir, "package"), pageDir, b)
+	b.WriteString("<ul>\n")
+	for _, pkg := range p.Packages {
+		b.WriteString("<li>")
+		f.renderNavPackage(pkg, path.Join(pkgDir, pkg.GetFileName()), pageDir, b)
+		b.WriteString("</li>\n")
+	}
+	for _, mod := range p.Modules {
+		modDir := path.Join(pkgDir, mod.GetFileName())
+		b.WriteString("<li>")
+		f.writeNavLink(mod.GetName(), f.ToLinkPath(modDir, "module"), pageDir, b)
+		b.WriteString("<ul>\n")
+		f.renderNavMembers(mod.Structs, mod.Traits, mod.Functions, modDir, pageDir, b)
+		b.WriteString("</ul>\n</li>\n")
+	}
+	f.renderNavMembers(p.Structs, p.Traits, p.Functions, pkgDir, pageDir, b)
+	b.WriteString("</ul>\n")
+}
+
+func (f *HTML) renderNavMembers(structs []*document.Struct, traits []*document.Trait, functions []*document.Function, dir, pageDir string, b *strings.Builder) {
+	for _, s := range structs {
+		f.renderNavMember(s.GetName(), f.ToLinkPath(path.Join(dir, s.GetFileName()), "struct"), pageDir, b)
+	}
+	for _, tr := range traits {
+		f.renderNavMember(tr.GetName(), f.ToLinkPath(path.Join(dir, tr.GetFileName()), "trait"), pageDir, b)
+	}
+	for _, fn := range functions {
+		f.renderNavMember(fn.GetName(), f.ToLinkPath(path.Join(dir, fn.GetFileName()), "function"), pageDir, b)
+	}
+}
+
+func (f *HTML) renderNavMember(name, target, pageDir string, b *strings.Builder) {
+	b.WriteString("<li>")
+	f.writeNavLink(name, target, pageDir, b)
+	b.WriteString("</li>\n")
+}
+
+func (f *HTML) writeNavLink(name, target, pageDir string, b *strings.Builder) {
+	link, err := filepath.Rel(pageDir, target)
+	if err != nil {
+		link = target
+	}
+	fmt.Fprintf(b, "<a href=\"%s\">%s</a>", html.EscapeString(filepath.ToSlash(link)), html.EscapeString(name))
+}
+
+// pageTitle returns the text of the first top-level heading, without inline code marks.
+func pageTitle(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "# ") {
+			return strings.ReplaceAll(strings.TrimSpace(line[2:]), "`", "")
+		}
+	}
+	return ""
+}
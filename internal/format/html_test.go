@@ -0,0 +1,137 @@
+package format
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLToFilePath(t *testing.T) {
+	f := HTML{}
+
+	text := f.ToFilePath("pkg/mod/Struct", "struct")
+	assert.Equal(t, text, "pkg/mod/Struct.html")
+
+	text = f.ToFilePath("pkg/mod", "module")
+	assert.Equal(t, text, "pkg/mod/index.html")
+
+	text = f.ToFilePath("pkg", "package")
+	assert.Equal(t, text, "pkg/index.html")
+}
+
+func TestHTMLToLinkPath(t *testing.T) {
+	f := HTML{}
+
+	text := f.ToLinkPath("pkg/mod/Struct", "struct")
+	assert.Equal(t, text, "pkg/mod/Struct.html")
+
+	text = f.ToLinkPath("pkg", "package")
+	assert.Equal(t, text, "pkg/index.html")
+}
+
+func TestHTMLProcessPage(t *testing.T) {
+	form := HTML{}
+	templ, err := document.LoadTemplates(&form, "")
+	assert.Nil(t, err)
+
+	docs := document.Docs{
+		Decl: &document.Package{
+			MemberName: document.MemberName{Name: "pkg"},
+			MemberKind: document.MemberKind{Kind: "package"},
+			Modules: []*document.Module{
+				{
+					MemberName: document.MemberName{Name: "mod"},
+					MemberKind: document.MemberKind{Kind: "module"},
+					Structs: []*document.Struct{
+						{
+							MemberName: document.MemberName{Name: "Struct"},
+							MemberKind: document.MemberKind{Kind: "struct"},
+						},
+					},
+				},
+			},
+			Functions: []*document.Function{
+				{
+					MemberName: document.MemberName{Name: "func"},
+					MemberKind: document.MemberKind{Kind: "function"},
+				},
+			},
+		},
+	}
+	proc := document.NewProcessor(&docs, &form, templ, &document.Config{})
+	proc.ExportDocs = &docs
+
+	text, err := form.ProcessPage("# `Struct`\n\nSee [`pkg`](../index.html).\n", "pkg/mod", proc)
+	assert.Nil(t, err)
+
+	assert.Contains(t, text, "<title>Struct</title>")
+	assert.Contains(t, text, "<h1 id=\"struct\"><code>Struct</code></h1>\n<p>See <a href=\"../index.html\"><code>pkg</code></a>.</p>")
+	assert.Contains(t, text, "<a href=\"../index.html\">pkg</a><ul>\n"+
+		"<li><a href=\"index.html\">mod</a><ul>\n"+
+		"<li><a href=\"Struct.html\">Struct</a></li>\n"+
+		"</ul>\n</li>\n"+
+		"<li><a href=\"../func.html\">func</a></li>\n"+
+		"</ul>\n")
+	assert.True(t, strings.HasPrefix(text, "<!DOCTYPE html>"))
+}
+
+func TestHTMLConvertPage(t *testing.T) {
+	form := HTML{}
+	templ, err := document.LoadTemplates(&form, "")
+	assert.Nil(t, err)
+
+	docs := document.Docs{
+		Decl: &document.Package{
+			MemberName: document.MemberName{Name: "pkg"},
+			MemberKind: document.MemberKind{Kind: "package"},
+		},
+	}
+	pkgProc := document.NewProcessor(&docs, &form, templ, &document.Config{})
+	pkgProc.ExportDocs = &docs
+	proc := document.NewProcessor(nil, &form, templ, &document.Config{})
+
+	text, file, err := form.ConvertPage("# Guide\n\nSee [setup](setup.md#install), [home](../_index.md) and [web](https://example.com/a.md).\n",
+		"site/guide/intro.md", "guide", proc, []*document.Processor{pkgProc})
+	assert.Nil(t, err)
+	assert.Equal(t, "site/guide/intro.html", file)
+	assert.Contains(t, text, "<title>Guide</title>")
+	assert.Contains(t, text, "<p>See <a href=\"setup.html#install\">setup</a>, <a href=\"../index.html\">home</a> "+
+		"and <a href=\"https://example.com/a.md\">web</a>.</p>")
+	assert.Contains(t, text, "<a href=\"../pkg/index.html\">pkg</a>")
+
+	_, file, err = form.ConvertPage("# Home\n", "site/_index.md", ".", proc, []*document.Processor{pkgProc})
+	assert.Nil(t, err)
+	assert.Equal(t, "site/index.html", file)
+
+	_, file, err = form.ConvertPage("# Home\n", "site/index.md", ".", proc, []*document.Processor{pkgProc})
+	assert.Nil(t, err)
+	assert.Equal(t, "site/index.html", file)
+}
+
+func TestHTMLOutput(t *testing.T) {
+	f := HTML{}
+	assert.Equal(t, f.Output("site"), "site")
+}
+
+func TestHTMLGitIgnore(t *testing.T) {
+	f := HTML{}
+	gi := f.GitIgnore("src", "site", []document.PackageSource{})
+
+	assert.Contains(t, gi, "/src/*.json")
+	assert.Contains(t, gi, "/site/")
+	assert.Contains(t, gi, "/test/")
+}
+
+func TestHTMLCreateDirs(t *testing.T) {
+	f := HTML{}
+	testCreateDirs(&f, t, "docs/site", []string{
+		".",
+		"docs",
+		"docs/site",
+		"docs/src",
+		"docs/src/_index.md",
+		"docs/test",
+	})
+}
@@ -56,6 +56,11 @@ func (f *Hugo) ToLinkPath(p string, kind string) string {
 	return fmt.Sprintf("{{< ref \"%s\" >}}", p)
 }
 
+// ToURL converts a member path to the URL of its page, following Hugo's default lower-case pretty URLs.
+func (f *Hugo) ToURL(p string, kind string) string {
+	return strings.ToLower(p) + "/"
+}
+
 func (f *Hugo) Input(in string, sources []document.PackageSource) string {
 	return in
 }
@@ -136,5 +141,5 @@ func (f *Hugo) Clean(out, tests string) error {
 	if err := emptyDir(out); err != nil {
 		return err
 	}
-	return emptyDir(tests)
+	return document.CleanDocTests(tests)
 }
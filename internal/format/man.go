@@ -0,0 +1,149 @@
+package format
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/mlange-42/modo/internal/util"
+)
+
+const manLinkPrefix = "man:"
+const manSection = "3"
+
+var manLinkRegex = regexp.MustCompile(`\[([^\]]*)\]\(man:([^)#\s]*)(#[^)\s]*)?\)`)
+
+type Man struct{}
+
+type manPage struct {
+	Title   string
+	Section string
+	Content string
+	SeeAlso []string
+}
+
+func (f *Man) Accepts(files []string) error {
+	return nil
+}
+
+func (f *Man) ProcessMarkdown(element any, text string, proc *document.Processor) (string, error) {
+	return text, nil
+}
+
+// ProcessPage converts the Markdown of a page to a man page in roff format.
+// Internal links are replaced by their text, and pages referenced
+// by links are listed in a "SEE ALSO" section.
+func (f *Man) ProcessPage(text string, dir string, proc *document.Processor) (string, error) {
+	baseDir := "."
+	if proc.ExportDocs != nil {
+		baseDir = path.Dir(proc.PackageDir())
+	}
+	seeAlso := []string{}
+	text = manLinkRegex.ReplaceAllStringFunc(text, func(link string) string {
+		parts := manLinkRegex.FindStringSubmatch(link)
+		if parts[3] == "" {
+			if page := flatLinkTarget(dir, parts[2], baseDir); !slices.Contains(seeAlso, page) {
+				seeAlso = append(seeAlso, page)
+			}
+		}
+		return parts[1]
+	})
+
+	page := manPage{
+		Title:   strings.ReplaceAll(manTitle(text), `"`, `\(dq`),
+		Section: manSection,
+		Content: markdownToRoff(text),
+		SeeAlso: seeAlso,
+	}
+	b := strings.Builder{}
+	if err := proc.Template.ExecuteTemplate(&b, "man_page.roff", &page); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ConvertPage converts a handwritten Markdown page to a man page.
+// The page is written to the output directory, with a flattened name like API pages.
+// Relative links to other Markdown pages are listed in the "SEE ALSO" section.
+func (f *Man) ConvertPage(text string, file string, dir string, proc *document.Processor, procs []*document.Processor) (string, string, error) {
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	text = mdPageLinkRegex.ReplaceAllString(text, "]("+manLinkPrefix+"$1$2)")
+	content, err := f.ProcessPage(text, dir, proc)
+	if err != nil {
+		return "", "", err
+	}
+	target := flatPageName(path.Join(dir, name)) + "." + manSection
+	return content, filepath.Join(proc.Config.OutputDir, target), nil
+}
+
+func (f *Man) WriteAuxiliary(p *document.Package, dir string, proc *document.Processor) error {
+	return nil
+}
+
+func (f *Man) ToFilePath(p string, kind string) string {
+	if len(p) == 0 {
+		return p
+	}
+	return flatPageName(p) + "." + manSection
+}
+
+func (f *Man) ToLinkPath(p string, kind string) string {
+	return manLinkPrefix + p
+}
+
+func (f *Man) Input(in string, sources []document.PackageSource) string {
+	return in
+}
+
+func (f *Man) Output(out string) string {
+	return path.Join(out, "man"+manSection)
+}
+
+func (f *Man) GitIgnore(in, out string, sources []document.PackageSource) []string {
+	return []string{
+		"# files generated by 'mojo doc'",
+		fmt.Sprintf("/%s/*.json", in),
+		"# files generated by Modo",
+		fmt.Sprintf("/%s/", out),
+		"# test file generated by Modo",
+		"/test/",
+	}
+}
+
+func (f *Man) CreateDirs(base, in, out string, sources []document.PackageSource, _ *template.Template) error {
+	inDir, outDir := path.Join(base, in), path.Join(base, f.Output(out))
+	testDir := path.Join(base, "test")
+	if err := util.MkDirs(inDir); err != nil {
+		return err
+	}
+	if err := util.MkDirs(outDir); err != nil {
+		return err
+	}
+	return util.MkDirs(testDir)
+}
+
+func (f *Man) Clean(out, tests string) error {
+	if err := emptyDir(out); err != nil {
+		return err
+	}
+	return document.CleanDocTests(tests)
+}
+
+// manTitle returns the text of the first top-level heading, without inline code marks.
+// Falls back to the first heading of any level, as for single methods.
+func manTitle(text string) string {
+	if title := pageTitle(text); title != "" {
+		return title
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if m := mdHeadingRegex.FindStringSubmatch(line); m != nil {
+			return strings.ReplaceAll(strings.TrimSpace(m[2]), "`", "")
+		}
+	}
+	return ""
+}
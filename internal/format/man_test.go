@@ -0,0 +1,70 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManToFilePath(t *testing.T) {
+	f := Man{}
+
+	text := f.ToFilePath("pkg/mod/Struct", "struct")
+	assert.Equal(t, text, "pkg.mod.Struct.3")
+
+	text = f.ToFilePath("pkg/mod", "module")
+	assert.Equal(t, text, "pkg.mod.3")
+
+	text = f.ToFilePath("pkg", "package")
+	assert.Equal(t, text, "pkg.3")
+}
+
+func TestManProcessPage(t *testing.T) {
+	f := Man{}
+
+	docs := document.Docs{
+		Decl: &document.Package{
+			MemberName: document.MemberName{Name: "pkg"},
+			MemberKind: document.MemberKind{Kind: "package"},
+		},
+	}
+	templ, err := document.LoadTemplates(&f, "")
+	assert.Nil(t, err)
+	proc := document.NewProcessor(&docs, &f, templ, &document.Config{OutputDir: "out"})
+	proc.ExportDocs = &docs
+
+	text, err := f.ProcessPage(
+		"# `Struct`\n\nSee [`Other`](man:Other), [`method`](man:../Trait#method) and [docs](https://example.com).\n",
+		"pkg/mod", proc)
+	assert.Nil(t, err)
+	assert.Equal(t,
+		".TH \"Struct\" 3 \"\" \"\" \"Mojo API reference\"\n"+
+			".SH \"\\fBStruct\\fR\"\n"+
+			".PP\n"+
+			"See \\fBOther\\fR, \\fBmethod\\fR and docs <https://example.com>.\n"+
+			".SH \"SEE ALSO\"\n"+
+			"\\fBpkg.mod.Other\\fR(3)\n",
+		text)
+}
+
+func TestManConvertPage(t *testing.T) {
+	f := Man{}
+	templ, err := document.LoadTemplates(&f, "")
+	assert.Nil(t, err)
+	proc := document.NewProcessor(nil, &f, templ, &document.Config{OutputDir: "out"})
+
+	text, file, err := f.ConvertPage(
+		"# Guide\n\nSee [setup](setup.md), [install](setup.md#install) and [web](https://example.com/a.md).\n",
+		"out/guide/intro.md", "guide", proc, []*document.Processor{proc})
+	assert.Nil(t, err)
+	assert.Equal(t, "out/guide.intro.3", file)
+	assert.Equal(t,
+		".TH \"Guide\" 3 \"\" \"\" \"Mojo API reference\"\n"+
+			".SH \"Guide\"\n"+
+			".PP\n"+
+			"See setup, install and web <https://example.com/a.md>.\n"+
+			".SH \"SEE ALSO\"\n"+
+			"\\fBguide.setup\\fR(3)\n",
+		text)
+}
@@ -0,0 +1,557 @@
+package format
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var mdHeadingRegex = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?\s*#*\s*$`)
+
+// mdPageLinkRegex matches relative links to Markdown pages, with optional anchor.
+var mdPageLinkRegex = regexp.MustCompile(`\]\(([^)#\s:]*)\.md(#[^)\s]*)?\)`)
+var mdListItemRegex = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])(\s+|$)`)
+var mdTableSepRegex = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+var mdRuleRegex = regexp.MustCompile(`^ {0,3}([-*_])(\s*[-*_]){2,}\s*$`)
+var urlSchemeRegex = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.\-]*):`)
+
+// markdownRenderer emits the blocks and inline elements found by [renderMarkdown] and [renderInline].
+// Block methods receive raw Markdown for nested content and write to the builder.
+// Inline methods return the rendered element.
+type markdownRenderer interface {
+	// paragraph renders a paragraph with the given raw inline Markdown.
+	paragraph(b *strings.Builder, text string)
+	// heading renders a heading with the given level and raw inline Markdown.
+	heading(b *strings.Builder, level int, text string)
+	// rule renders a horizontal rule.
+	rule(b *strings.Builder)
+	// codeBlock renders a fenced code block, with unindented lines.
+	codeBlock(b *strings.Builder, lang string, lines []string)
+	// blockQuote renders a block quote with the given raw Markdown content.
+	blockQuote(b *strings.Builder, text string)
+	// list renders a list with the raw Markdown content of its items.
+	list(b *strings.Builder, ordered bool, items []string)
+	// table renders a table with raw inline Markdown cells.
+	// Alignments are "left", "center", "right" or empty.
+	table(b *strings.Builder, header []string, align []string, rows [][]string)
+
+	// text renders plain text.
+	text(s string) string
+	// code renders a code span.
+	code(s string) string
+	// link renders a link or an image, with the raw inline Markdown text.
+	link(text string, url string, image bool) string
+	// autolink renders an external URL in angle brackets.
+	autolink(url string) string
+	// emphasis renders emphasized or strong text, with already rendered content.
+	emphasis(text string, strong bool) string
+}
+
+// markdownToHTML converts Markdown to HTML.
+// It supports the subset of Markdown produced by Modo's templates and commonly used in docstrings:
+// headings, paragraphs, lists, block quotes, tables, rules, code blocks, and inline markup.
+// Raw HTML is escaped.
+func markdownToHTML(text string) string {
+	return renderMarkdown(text, htmlRenderer{})
+}
+
+// renderMarkdown splits Markdown into blocks and renders them with the given renderer.
+func renderMarkdown(text string, r markdownRenderer) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	b := strings.Builder{}
+	para := []string{}
+
+	flush := func() {
+		if len(para) > 0 {
+			r.paragraph(&b, strings.Join(para, "\n"))
+			para = para[:0]
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" {
+			flush()
+			i++
+			continue
+		}
+		if marker := fenceMarker(trimmed); marker != "" && leadingSpaces(line) < 4 {
+			flush()
+			i = parseCodeBlock(lines, i, marker, &b, r)
+			continue
+		}
+		if m := mdHeadingRegex.FindStringSubmatch(line); m != nil {
+			flush()
+			r.heading(&b, len(m[1]), m[2])
+			i++
+			continue
+		}
+		if mdRuleRegex.MatchString(line) {
+			flush()
+			r.rule(&b)
+			i++
+			continue
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			flush()
+			i = parseBlockQuote(lines, i, &b, r)
+			continue
+		}
+		if mdListItemRegex.MatchString(line) {
+			flush()
+			i = parseList(lines, i, &b, r)
+			continue
+		}
+		if len(para) == 0 && strings.Contains(line, "|") && i+1 < len(lines) &&
+			strings.Contains(lines[i+1], "-") && mdTableSepRegex.MatchString(lines[i+1]) {
+			i = parseTable(lines, i, &b, r)
+			continue
+		}
+		para = append(para, trimmed)
+		i++
+	}
+	flush()
+
+	return b.String()
+}
+
+func parseCodeBlock(lines []string, start int, marker string, b *strings.Builder, r markdownRenderer) int {
+	indent := leadingSpaces(lines[start])
+	info := strings.TrimSpace(strings.TrimSpace(lines[start])[len(marker):])
+	lang := ""
+	if fields := strings.FieldsFunc(info, func(r rune) bool { return r == ' ' || r == '{' || r == ',' }); len(fields) > 0 {
+		lang = fields[0]
+	}
+	code := []string{}
+	i := start + 1
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, marker) && strings.Trim(trimmed, marker[:1]) == "" {
+			i++
+			break
+		}
+		line := lines[i]
+		n := min(indent, leadingSpaces(line))
+		code = append(code, line[n:])
+	}
+	r.codeBlock(b, lang, code)
+	return i
+}
+
+func parseBlockQuote(lines []string, start int, b *strings.Builder, r markdownRenderer) int {
+	inner := []string{}
+	i := start
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, ">") {
+			break
+		}
+		trimmed = strings.TrimPrefix(trimmed, ">")
+		inner = append(inner, strings.TrimPrefix(trimmed, " "))
+	}
+	r.blockQuote(b, strings.Join(inner, "\n"))
+	return i
+}
+
+func parseList(lines []string, start int, b *strings.Builder, r markdownRenderer) int {
+	first := mdListItemRegex.FindStringSubmatch(lines[start])
+	ordered := !strings.ContainsAny(first[2], "-*+")
+	indent := len(first[1])
+
+	items := [][]string{}
+	i := start
+	for i < len(lines) {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			next := i + 1
+			for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+				next++
+			}
+			if next >= len(lines) || leadingSpaces(lines[next]) <= indent && !isListItem(lines[next], indent, ordered) {
+				break
+			}
+			items[len(items)-1] = append(items[len(items)-1], "")
+			i++
+			continue
+		}
+		if isListItem(line, indent, ordered) {
+			m := mdListItemRegex.FindStringSubmatch(line)
+			items = append(items, []string{line[len(m[0]):]})
+			i++
+			continue
+		}
+		if mdListItemRegex.MatchString(line) && leadingSpaces(line) <= indent {
+			break
+		}
+		if leadingSpaces(line) > indent {
+			items[len(items)-1] = append(items[len(items)-1], strings.TrimPrefix(line, strings.Repeat(" ", min(leadingSpaces(line), indent+4))))
+			i++
+			continue
+		}
+		prev := items[len(items)-1]
+		if prev[len(prev)-1] == "" || fenceMarker(strings.TrimSpace(line)) != "" ||
+			mdHeadingRegex.MatchString(line) || strings.HasPrefix(strings.TrimSpace(line), ">") {
+			break
+		}
+		items[len(items)-1] = append(prev, line)
+		i++
+	}
+
+	texts := make([]string, len(items))
+	for n, item := range items {
+		texts[n] = strings.Join(item, "\n")
+	}
+	r.list(b, ordered, texts)
+	return i
+}
+
+func isListItem(line string, indent int, ordered bool) bool {
+	m := mdListItemRegex.FindStringSubmatch(line)
+	if m == nil || len(m[1]) != indent {
+		return false
+	}
+	return ordered == !strings.ContainsAny(m[2], "-*+")
+}
+
+func parseTable(lines []string, start int, b *strings.Builder, r markdownRenderer) int {
+	header := splitTableRow(lines[start])
+	align := splitTableRow(lines[start+1])
+	for i, a := range align {
+		switch {
+		case strings.HasPrefix(a, ":") && strings.HasSuffix(a, ":"):
+			align[i] = "center"
+		case strings.HasSuffix(a, ":"):
+			align[i] = "right"
+		case strings.HasPrefix(a, ":"):
+			align[i] = "left"
+		default:
+			align[i] = ""
+		}
+	}
+	rows := [][]string{}
+	i := start + 2
+	for ; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" || !strings.Contains(lines[i], "|") {
+			break
+		}
+		rows = append(rows, splitTableRow(lines[i]))
+	}
+	r.table(b, header, align, rows)
+	return i
+}
+
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	cells := []string{}
+	start := 0
+	code := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '`':
+			code = !code
+		case '|':
+			if !code {
+				cells = append(cells, strings.TrimSpace(line[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(cells, strings.TrimSpace(line[start:]))
+}
+
+// renderInline renders inline Markdown with the given renderer: code spans, links, images, emphasis and escapes.
+func renderInline(s string, r markdownRenderer) string {
+	b := strings.Builder{}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch c {
+		case '`':
+			n := runLength(s[i:], '`')
+			if end := findRun(s[i+n:], '`', n); end >= 0 {
+				code := s[i+n : i+n+end]
+				if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+					code = code[1 : len(code)-1]
+				}
+				b.WriteString(r.code(code))
+				i += n + end + n
+				continue
+			}
+			b.WriteString(s[i : i+n])
+			i += n
+			continue
+		case '\\':
+			if i+1 < len(s) && strings.IndexByte("\\`*_{}[]()#+-.!|<>~", s[i+1]) >= 0 {
+				b.WriteString(r.text(s[i+1 : i+2]))
+				i += 2
+				continue
+			}
+		case '!', '[':
+			image := c == '!'
+			if image && (i+1 >= len(s) || s[i+1] != '[') {
+				break
+			}
+			start := i
+			if image {
+				start++
+			}
+			if text, url, n, ok := parseLink(s[start:]); ok {
+				if isSafeURL(url) {
+					b.WriteString(r.link(text, url, image))
+				} else {
+					b.WriteString(renderInline(text, r))
+				}
+				i = start + n
+				continue
+			}
+		case '<':
+			if end := strings.IndexByte(s[i:], '>'); end > 0 {
+				url := s[i+1 : i+end]
+				if (strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")) && !strings.ContainsAny(url, " \n<") {
+					b.WriteString(r.autolink(url))
+					i += end + 1
+					continue
+				}
+			}
+		case '*':
+			n := min(runLength(s[i:], '*'), 2)
+			delim := s[i : i+n]
+			if i+n < len(s) && s[i+n] != ' ' {
+				if end := strings.Index(s[i+n:], delim); end > 0 && s[i+n+end-1] != ' ' {
+					b.WriteString(r.emphasis(renderInline(s[i+n:i+n+end], r), n == 2))
+					i += n + end + n
+					continue
+				}
+			}
+			b.WriteString(delim)
+			i += n
+			continue
+		case '_':
+			n := min(runLength(s[i:], '_'), 2)
+			if (i == 0 || !isWordByte(s[i-1])) && i+n < len(s) && s[i+n] != ' ' {
+				if end := findUnderscoreClose(s[i+n:], n); end > 0 {
+					b.WriteString(r.emphasis(renderInline(s[i+n:i+n+end], r), n == 2))
+					i += n + end + n
+					continue
+				}
+			}
+			b.WriteString(r.text(s[i : i+n]))
+			i += n
+			continue
+		}
+		b.WriteString(r.text(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// findUnderscoreClose finds the closing delimiter of n underscores for emphasis.
+// Like in CommonMark, underscores inside words don't close emphasis.
+func findUnderscoreClose(s string, n int) int {
+	delim := strings.Repeat("_", n)
+	for i := 1; i+n <= len(s); i++ {
+		if s[i:i+n] != delim || s[i-1] == ' ' {
+			continue
+		}
+		if i+n < len(s) && (s[i+n] == '_' || isWordByte(s[i+n])) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// isSafeURL reports whether a link target is a relative URL or an http(s) URL.
+// Other schemes, like javascript:, are rejected.
+func isSafeURL(url string) bool {
+	m := urlSchemeRegex.FindStringSubmatch(strings.TrimSpace(url))
+	if m == nil {
+		return true
+	}
+	scheme := strings.ToLower(m[1])
+	return scheme == "http" || scheme == "https"
+}
+
+// parseLink parses a Markdown link of the form [text](url "title").
+// It returns the text, the URL and the number of bytes consumed.
+func parseLink(s string) (text string, url string, n int, ok bool) {
+	depth := 0
+	textEnd := -1
+	for i := 0; i < len(s) && textEnd < 0; i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				textEnd = i
+			}
+		}
+	}
+	if textEnd < 0 || textEnd+1 >= len(s) || s[textEnd+1] != '(' {
+		return "", "", 0, false
+	}
+	// The destination may contain balanced parentheses.
+	urlEnd := -1
+	depth = 0
+	for i := textEnd + 2; i < len(s) && urlEnd < 0; i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				urlEnd = i - (textEnd + 2)
+			}
+			depth--
+		}
+	}
+	if urlEnd < 0 {
+		return "", "", 0, false
+	}
+	dest := strings.TrimSpace(s[textEnd+2 : textEnd+2+urlEnd])
+	if idx := strings.IndexAny(dest, " \t\n"); idx >= 0 {
+		dest = dest[:idx]
+	}
+	dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+	return s[1:textEnd], dest, textEnd + 2 + urlEnd + 1, true
+}
+
+// htmlRenderer renders Markdown to HTML.
+type htmlRenderer struct{}
+
+func (r htmlRenderer) paragraph(b *strings.Builder, text string) {
+	fmt.Fprintf(b, "<p>%s</p>\n", renderInline(text, r))
+}
+
+func (r htmlRenderer) heading(b *strings.Builder, level int, text string) {
+	fmt.Fprintf(b, "<h%d id=\"%s\">%s</h%d>\n", level, html.EscapeString(headingSlug(text)), renderInline(text, r), level)
+}
+
+func (r htmlRenderer) rule(b *strings.Builder) {
+	b.WriteString("<hr>\n")
+}
+
+func (r htmlRenderer) codeBlock(b *strings.Builder, lang string, lines []string) {
+	if lang != "" {
+		fmt.Fprintf(b, "<pre><code class=\"language-%s\">", html.EscapeString(lang))
+	} else {
+		b.WriteString("<pre><code>")
+	}
+	for _, line := range lines {
+		b.WriteString(html.EscapeString(line))
+		b.WriteRune('\n')
+	}
+	b.WriteString("</code></pre>\n")
+}
+
+func (r htmlRenderer) blockQuote(b *strings.Builder, text string) {
+	fmt.Fprintf(b, "<blockquote>\n%s</blockquote>\n", renderMarkdown(text, r))
+}
+
+func (r htmlRenderer) list(b *strings.Builder, ordered bool, items []string) {
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	fmt.Fprintf(b, "<%s>\n", tag)
+	for _, item := range items {
+		fmt.Fprintf(b, "<li>%s</li>\n", strings.TrimSuffix(unwrapParagraph(renderMarkdown(item, r)), "\n"))
+	}
+	fmt.Fprintf(b, "</%s>\n", tag)
+}
+
+// unwrapParagraph removes the paragraph tags of a leading paragraph,
+// to render tight list items.
+func unwrapParagraph(s string) string {
+	if !strings.HasPrefix(s, "<p>") {
+		return s
+	}
+	end := strings.Index(s, "</p>\n")
+	return s[3:end] + "\n" + s[end+5:]
+}
+
+func (r htmlRenderer) table(b *strings.Builder, header []string, align []string, rows [][]string) {
+	cell := func(tag string, col int, text string) {
+		attr := ""
+		if col < len(align) && align[col] != "" {
+			attr = fmt.Sprintf(" style=\"text-align: %s\"", align[col])
+		}
+		fmt.Fprintf(b, "<%s%s>%s</%s>", tag, attr, renderInline(text, r), tag)
+	}
+
+	b.WriteString("<table>\n<thead>\n<tr>")
+	for col, text := range header {
+		cell("th", col, text)
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+	for _, row := range rows {
+		b.WriteString("<tr>")
+		for col, text := range row {
+			cell("td", col, text)
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+}
+
+func (r htmlRenderer) text(s string) string {
+	return html.EscapeString(s)
+}
+
+func (r htmlRenderer) code(s string) string {
+	return fmt.Sprintf("<code>%s</code>", html.EscapeString(s))
+}
+
+func (r htmlRenderer) link(text string, url string, image bool) string {
+	if image {
+		return fmt.Sprintf("<img src=\"%s\" alt=\"%s\">", html.EscapeString(url), html.EscapeString(text))
+	}
+	return fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(url), renderInline(text, r))
+}
+
+func (r htmlRenderer) autolink(url string) string {
+	return fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(url), html.EscapeString(url))
+}
+
+func (r htmlRenderer) emphasis(text string, strong bool) string {
+	if strong {
+		return fmt.Sprintf("<strong>%s</strong>", text)
+	}
+	return fmt.Sprintf("<em>%s</em>", text)
+}
+
+// headingSlug creates an anchor from a heading.
+// It keeps letters, digits, underscores and dashes, and replaces spaces by dashes.
+func headingSlug(s string) string {
+	b := strings.Builder{}
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case r == ' ':
+			b.WriteRune('-')
+		case r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func leadingSpaces(line string) int {
+	return runLength(line, ' ')
+}
@@ -0,0 +1,44 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownToHTML(t *testing.T) {
+	tests := []struct {
+		markdown string
+		html     string
+	}{
+		{"# Title", "<h1 id=\"title\">Title</h1>\n"},
+		{"### `__init__`", "<h3 id=\"__init__\"><code>__init__</code></h3>\n"},
+		{"A\nparagraph.\n\nAnother one.", "<p>A\nparagraph.</p>\n<p>Another one.</p>\n"},
+		{"A **bold**, *italic* and `<code>` text with <T> & snake_case_name.",
+			"<p>A <strong>bold</strong>, <em>italic</em> and <code>&lt;code&gt;</code> text with &lt;T&gt; &amp; snake_case_name.</p>\n"},
+		{"Also __bold__, _italic_, __*nested*__ and x__y__z.",
+			"<p>Also <strong>bold</strong>, <em>italic</em>, <strong><em>nested</em></strong> and x__y__z.</p>\n"},
+		{"No _ emphasis_ or __open.", "<p>No _ emphasis_ or __open.</p>\n"},
+		{"[x](javascript:alert(1)), [y](JavaScript:void) and ![z](data:image/png;base64,AAA)",
+			"<p>x, y and z</p>\n"},
+		{"[a](https://example.com), [b](../b.html#c) and [c](mailto:me@example.com)",
+			"<p><a href=\"https://example.com\">a</a>, <a href=\"../b.html#c\">b</a> and c</p>\n"},
+		{"A [`link`](pkg/Struct.html#__init__), an ![image](img.png) and <https://example.com>.",
+			"<p>A <a href=\"pkg/Struct.html#__init__\"><code>link</code></a>, an <img src=\"img.png\" alt=\"image\"> " +
+				"and <a href=\"https://example.com\">https://example.com</a>.</p>\n"},
+		{"[not a link] and \\*escaped\\*", "<p>[not a link] and *escaped*</p>\n"},
+		{"```mojo {doctest=\"x\"}\nfn f[T: A]() -> T:\n    pass\n```",
+			"<pre><code class=\"language-mojo\">fn f[T: A]() -&gt; T:\n    pass\n</code></pre>\n"},
+		{" - a\n - b\n   continued\n\nText", "<ul>\n<li>a</li>\n<li>b\ncontinued</li>\n</ul>\n<p>Text</p>\n"},
+		{"1. a\n2. b\n   - nested", "<ol>\n<li>a</li>\n<li>b\n<ul>\n<li>nested</li>\n</ul></li>\n</ol>\n"},
+		{"> quote\n> more", "<blockquote>\n<p>quote\nmore</p>\n</blockquote>\n"},
+		{"| A | `a|b` |\n|:--|:-:|\n| 1 | 2 |",
+			"<table>\n<thead>\n<tr><th style=\"text-align: left\">A</th><th style=\"text-align: center\"><code>a|b</code></th></tr>\n" +
+				"</thead>\n<tbody>\n<tr><td style=\"text-align: left\">1</td><td style=\"text-align: center\">2</td></tr>\n</tbody>\n</table>\n"},
+		{"---", "<hr>\n"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.html, markdownToHTML(tt.markdown), "Markdown: %q", tt.markdown)
+	}
+}
@@ -1,12 +1,15 @@
 package format
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
 
@@ -15,26 +18,34 @@ import (
 	"github.com/mlange-42/modo/internal/util"
 )
 
+const landingPageContentMdBook = `# Landing page
+
+JSON created by mojo doc should be placed next to this file.
+
+Additional documentation files go here, too.
+They will be processed for doc-tests, copied to folder 'site/src' and added to the book.
+`
+
+const mdBookSummaryFile = "SUMMARY.md"
+
 type MdBook struct{}
 
 type mdBookConfig struct {
 	Title string
 }
 
+type summary struct {
+	Landing string
+	Pages   string
+	Parts   []summaryPart
+}
+
+type summaryPart struct {
+	Title   string
+	Content string
+}
+
 func (f *MdBook) Accepts(files []string) error {
-	if len(files) > 1 {
-		return fmt.Errorf("mdBook formatter can process only a single JSON file, but %d is given", len(files))
-	}
-	if len(files) == 0 || files[0] == "" {
-		return nil
-	}
-	if s, err := os.Stat(files[0]); err == nil {
-		if s.IsDir() {
-			return fmt.Errorf("mdBook formatter can process only a single JSON file, but directory '%s' is given", files[0])
-		}
-	} else {
-		return err
-	}
 	return nil
 }
 
@@ -63,121 +74,170 @@ func (f *MdBook) ToLinkPath(p string, kind string) string {
 	return f.ToFilePath(p, kind)
 }
 
-func (f *MdBook) Clean(out, _ string) error {
-	dirs, err := os.ReadDir(out)
-	if err != nil {
+// ToURL converts a member path to the URL of the HTML page rendered by mdBook.
+func (f *MdBook) ToURL(p string, kind string) string {
+	return strings.TrimSuffix(f.ToFilePath(p, kind), ".md") + ".html"
+}
+
+// Clean removes the generated package directories and the SUMMARY.md from the output directory.
+// Package directories are recognized by their '_index.md'.
+// Other files are retained, like 'book.toml' and 'css/' in projects set up by earlier versions,
+// where the output directory is the book's root.
+func (f *MdBook) Clean(out, tests string) error {
+	entries, err := os.ReadDir(out)
+	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
-	for _, info := range dirs {
-		if !info.IsDir() {
-			continue
-		}
-		if info.Name() == "css" {
+	for _, entry := range entries {
+		if !entry.IsDir() {
 			continue
 		}
-		if err := emptyDir(path.Join(out, info.Name())); err != nil {
+		dir := path.Join(out, entry.Name())
+		exists, _, err := util.FileExists(path.Join(dir, "_index.md"))
+		if err != nil {
 			return err
 		}
+		if exists {
+			if err := os.RemoveAll(dir); err != nil {
+				return err
+			}
+		}
 	}
-
-	return nil
-}
-
-type summary struct {
-	Summary   string
-	Packages  string
-	Modules   string
-	Structs   string
-	Traits    string
-	Functions string
+	if err := os.Remove(path.Join(out, mdBookSummaryFile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return document.CleanDocTests(tests)
 }
 
+// writeSummary writes the combined SUMMARY.md to the output directory.
+// It contains additional Markdown pages from the input directories,
+// and a part for each package. Parts of other packages, from previously
+// processed JSON files, are retained.
 func (f *MdBook) writeSummary(p *document.Package, dir string, proc *document.Processor) error {
-	summary, err := f.renderSummary(p, proc)
+	summaryPath := path.Join(proc.Config.OutputDir, mdBookSummaryFile)
+
+	var parts []summaryPart
+	if content, err := os.ReadFile(summaryPath); err == nil {
+		parts = parseSummaryParts(string(content))
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	linkPath := proc.PackageDir()
+	part, err := f.renderPart(p, path.Dir(linkPath))
 	if err != nil {
 		return err
 	}
-	summaryPath := path.Join(dir, p.GetFileName(), "SUMMARY.md")
-	if proc.Config.DryRun {
-		return nil
+	if idx := slices.IndexFunc(parts, func(s summaryPart) bool { return s.Title == part.Title }); idx >= 0 {
+		parts[idx] = part
+	} else {
+		parts = append(parts, part)
 	}
-	if err := os.WriteFile(summaryPath, []byte(summary), 0644); err != nil {
+
+	landing, pages, err := f.collectPages(proc.Config.InputFiles)
+	if err != nil {
+		return err
+	}
+	text, err := f.renderSummary(landing, pages, parts, proc)
+	if err != nil {
 		return err
 	}
-	return nil
-}
-
-func (f *MdBook) renderSummary(p *document.Package, proc *document.Processor) (string, error) {
-	s := summary{}
-
-	pkgFile := f.ToLinkPath("", "package")
-	s.Summary = fmt.Sprintf("[`%s`](%s)", p.GetName(), pkgFile)
 
-	pkgs := strings.Builder{}
-	for _, p := range p.Packages {
-		if err := f.renderPackage(p, proc.Template, nil, &pkgs); err != nil {
-			return "", err
-		}
+	if proc.Config.DryRun {
+		return nil
 	}
-	s.Packages = pkgs.String()
+	return os.WriteFile(summaryPath, []byte(text), 0644)
+}
 
-	mods := strings.Builder{}
-	for _, m := range p.Modules {
-		if err := f.renderModule(m, nil, &mods); err != nil {
-			return "", err
-		}
+func (f *MdBook) renderSummary(landing string, pages []string, parts []summaryPart, proc *document.Processor) (string, error) {
+	s := summary{
+		Landing: landing,
+		Pages:   strings.Join(pages, ""),
+		Parts:   parts,
+	}
+	b := strings.Builder{}
+	if err := proc.Template.ExecuteTemplate(&b, "mdbook_summary.md", &s); err != nil {
+		return "", err
 	}
-	s.Modules = mods.String()
+	return b.String(), nil
+}
 
-	elems := strings.Builder{}
-	for _, elem := range p.Structs {
-		if err := f.renderModuleMember(elem, "", 0, &elems); err != nil {
-			return "", err
+// collectPages collects additional Markdown files from input directories.
+// A top-level index.md or README.md is used as landing page.
+func (f *MdBook) collectPages(inputs []string) (string, []string, error) {
+	landing := ""
+	pages := []string{}
+	for _, input := range inputs {
+		if input == "" {
+			continue
 		}
-	}
-	s.Structs = elems.String()
-	elems = strings.Builder{}
-	for _, elem := range p.Traits {
-		if err := f.renderModuleMember(elem, "", 0, &elems); err != nil {
-			return "", err
+		exists, isDir, err := util.FileExists(input)
+		if err != nil {
+			return "", nil, err
 		}
-	}
-	s.Traits = elems.String()
-	elems = strings.Builder{}
-	for _, elem := range p.Functions {
-		if err := f.renderModuleMember(elem, "", 0, &elems); err != nil {
-			return "", err
+		if !exists || !isDir {
+			continue
+		}
+		err = filepath.WalkDir(input, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(strings.ToLower(p), ".md") {
+				return nil
+			}
+			rel, err := filepath.Rel(input, p)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			title, err := pageTitleFromFile(p)
+			if err != nil {
+				return err
+			}
+			if title == "" {
+				title = strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+			}
+			if landing == "" && (rel == "index.md" || rel == "README.md") {
+				landing = fmt.Sprintf("[%s](%s)\n", title, rel)
+				return nil
+			}
+			pages = append(pages, fmt.Sprintf("- [%s](%s)\n", title, rel))
+			return nil
+		})
+		if err != nil {
+			return "", nil, err
 		}
 	}
-	s.Functions = elems.String()
+	return landing, pages, nil
+}
 
+func (f *MdBook) renderPart(p *document.Package, linkPath string) (summaryPart, error) {
 	b := strings.Builder{}
-	if err := proc.Template.ExecuteTemplate(&b, "mdbook_summary.md", &s); err != nil {
-		return "", err
+	if err := f.renderPackage(p, []string{linkPath}, 0, &b); err != nil {
+		return summaryPart{}, err
 	}
-
-	return b.String(), nil
+	return summaryPart{Title: p.GetName(), Content: b.String()}, nil
 }
 
-func (f *MdBook) renderPackage(pkg *document.Package, t *template.Template, linkPath []string, out *strings.Builder) error {
+func (f *MdBook) renderPackage(pkg *document.Package, linkPath []string, depth int, out io.Writer) error {
 	newPath := append([]string{}, linkPath...)
 	newPath = append(newPath, pkg.GetFileName())
+	pathStr := path.Join(newPath...)
 
-	pkgFile := f.ToLinkPath(path.Join(newPath...), "package")
-	fmt.Fprintf(out, "%-*s- [`%s`](%s))\n", 2*len(linkPath), "", pkg.GetName(), pkgFile)
+	pkgFile := f.ToLinkPath(pathStr, "package")
+	fmt.Fprintf(out, "%-*s- [`%s`](%s)\n", 2*depth, "", pkg.GetName(), pkgFile)
 	for _, p := range pkg.Packages {
-		if err := f.renderPackage(p, t, newPath, out); err != nil {
+		if err := f.renderPackage(p, newPath, depth+1, out); err != nil {
 			return err
 		}
 	}
 	for _, m := range pkg.Modules {
-		if err := f.renderModule(m, newPath, out); err != nil {
+		if err := f.renderModule(m, newPath, depth+1, out); err != nil {
 			return err
 		}
 	}
 
-	pathStr := path.Join(newPath...)
-	childDepth := 2*(len(newPath)-1) + 2
+	childDepth := 2 * (depth + 1)
 	for _, elem := range pkg.Structs {
 		if err := f.renderModuleMember(elem, pathStr, childDepth, out); err != nil {
 			return err
@@ -197,16 +257,16 @@ func (f *MdBook) renderPackage(pkg *document.Package, t *template.Template, link
 	return nil
 }
 
-func (f *MdBook) renderModule(mod *document.Module, linkPath []string, out *strings.Builder) error {
+func (f *MdBook) renderModule(mod *document.Module, linkPath []string, depth int, out io.Writer) error {
 	newPath := append([]string{}, linkPath...)
 	newPath = append(newPath, mod.GetFileName())
 
 	pathStr := path.Join(newPath...)
 
 	modFile := f.ToLinkPath(pathStr, "module")
-	fmt.Fprintf(out, "%-*s- [`%s`](%s)\n", 2*(len(newPath)-1), "", mod.GetName(), modFile)
+	fmt.Fprintf(out, "%-*s- [`%s`](%s)\n", 2*depth, "", mod.GetName(), modFile)
 
-	childDepth := 2*(len(newPath)-1) + 2
+	childDepth := 2 * (depth + 1)
 	for _, elem := range mod.Structs {
 		if err := f.renderModuleMember(elem, pathStr, childDepth, out); err != nil {
 			return err
@@ -231,43 +291,96 @@ func (f *MdBook) renderModuleMember(mem document.Named, pathStr string, depth in
 	return nil
 }
 
+// parseSummaryParts extracts the package parts from a SUMMARY.md created by Modo.
+func parseSummaryParts(text string) []summaryPart {
+	parts := []summaryPart{}
+	var current *summaryPart
+	content := strings.Builder{}
+
+	finish := func() {
+		if current != nil {
+			current.Content = strings.TrimSpace(content.String()) + "\n"
+			parts = append(parts, *current)
+		}
+		content.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if title, ok := strings.CutPrefix(line, "# "); ok {
+			finish()
+			current = nil
+			if title != "Summary" {
+				current = &summaryPart{Title: strings.TrimSpace(title)}
+			}
+			continue
+		}
+		if current != nil {
+			content.WriteString(line)
+			content.WriteRune('\n')
+		}
+	}
+	finish()
+
+	return parts
+}
+
+// pageTitleFromFile returns the first top-level heading of a Markdown file.
+func pageTitleFromFile(file string) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return pageTitle(strings.ReplaceAll(string(content), "\r\n", "\n")), nil
+}
+
 func (f *MdBook) Input(in string, sources []document.PackageSource) string {
-	file := sources[0].Name + ".json"
-	return file
+	return in
 }
 
 func (f *MdBook) Output(out string) string {
-	return ""
+	return path.Join(out, "src")
 }
 
 func (f *MdBook) GitIgnore(in, out string, sources []document.PackageSource) []string {
-	file := sources[0].Name + ".json"
 	return []string{
-		"# file generated by 'mojo doc'",
-		fmt.Sprintf("/%s", file),
+		"# files generated by 'mojo doc'",
+		fmt.Sprintf("/%s/*.json", in),
 		"# files generated by Modo",
-		fmt.Sprintf("/%s/", sources[0].Name),
+		fmt.Sprintf("/%s/%s/", out, "src"),
 		"# files generated by MdBook",
-		fmt.Sprintf("/%s/", "public"),
+		fmt.Sprintf("/%s/%s/", out, "public"),
 		"# test file generated by Modo",
 		"/test/",
 	}
 }
 
 func (f *MdBook) CreateDirs(base, in, out string, sources []document.PackageSource, templ *template.Template) error {
-	outDir := base
+	inDir, outDir := path.Join(base, in), path.Join(base, f.Output(out))
 	testDir := path.Join(base, "test")
+	if err := util.MkDirs(inDir); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path.Join(inDir, "index.md"), []byte(landingPageContentMdBook), 0644); err != nil {
+		return err
+	}
 	if err := util.MkDirs(outDir); err != nil {
 		return err
 	}
 	if err := util.MkDirs(testDir); err != nil {
 		return err
 	}
-	return f.createInitialFiles(sources[0].Name, outDir, templ)
+	return f.createInitialFiles(base, path.Join(base, out), templ)
 }
 
-func (f *MdBook) createInitialFiles(title, docDir string, templ *template.Template) error {
-	outFile := path.Join(docDir, "book.toml")
+func (f *MdBook) createInitialFiles(docDir, bookDir string, templ *template.Template) error {
+	gitInfo, err := document.GetGitOrigin(docDir)
+	if err != nil {
+		return err
+	}
+
+	outFile := path.Join(bookDir, "book.toml")
 	exists, _, err := util.FileExists(outFile)
 	if err != nil {
 		return err
@@ -277,7 +390,7 @@ func (f *MdBook) createInitialFiles(title, docDir string, templ *template.Templa
 		return nil
 	}
 
-	config := mdBookConfig{Title: title}
+	config := mdBookConfig{Title: gitInfo.Title}
 
 	b := bytes.Buffer{}
 	if err := templ.ExecuteTemplate(&b, "book.toml", &config); err != nil {
@@ -287,7 +400,7 @@ func (f *MdBook) createInitialFiles(title, docDir string, templ *template.Templa
 		return err
 	}
 
-	cssDir := path.Join(docDir, "css")
+	cssDir := path.Join(bookDir, "css")
 	cssFile := path.Join(cssDir, "mdbook.css")
 	exists, _, err = util.FileExists(cssFile)
 	if err != nil {
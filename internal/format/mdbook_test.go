@@ -1,7 +1,9 @@
 package format
 
 import (
-	"fmt"
+	"os"
+	"path"
+	"strings"
 	"testing"
 
 	"github.com/mlange-42/modo/internal/document"
@@ -12,11 +14,10 @@ func TestMdBookAccepts(t *testing.T) {
 	f := MdBook{}
 
 	err := f.Accepts([]string{"../../test"})
-	assert.NotNil(t, err)
-	assert.Equal(t, err.Error(), "mdBook formatter can process only a single JSON file, but directory '../../test' is given")
+	assert.Nil(t, err)
 
 	err = f.Accepts([]string{"../../main.go", "../../go.mod"})
-	assert.Equal(t, err.Error(), "mdBook formatter can process only a single JSON file, but 2 is given")
+	assert.Nil(t, err)
 }
 
 func TestMdBookToFilePath(t *testing.T) {
@@ -50,38 +51,42 @@ func TestMdBookInput(t *testing.T) {
 
 	assert.Equal(t, f.Input("src", []document.PackageSource{
 		{Name: "pkg", Path: []string{"src", "pkg"}},
-	}), "pkg.json")
+	}), "src")
 }
 
 func TestMdBookOutput(t *testing.T) {
 	f := MdBook{}
 
-	assert.Equal(t, f.Output("site"), "")
+	assert.Equal(t, f.Output("site"), "site/src")
 }
 
 func TestMdBookGitIgnore(t *testing.T) {
 	f := MdBook{}
 	gi := f.GitIgnore("src", "site", []document.PackageSource{{Name: "pkg"}})
 
-	assert.Contains(t, gi, "/pkg.json")
-	assert.Contains(t, gi, "/pkg/")
-	assert.Contains(t, gi, "/public/")
+	assert.Contains(t, gi, "/src/*.json")
+	assert.Contains(t, gi, "/site/src/")
+	assert.Contains(t, gi, "/site/public/")
 	assert.Contains(t, gi, "/test/")
 }
 
 func TestMdBookCreateDirs(t *testing.T) {
 	f := MdBook{}
-	testCreateDirs(&f, t, "docs", []string{
+	testCreateDirs(&f, t, "docs/site/src", []string{
 		".",
 		"docs",
-		"docs/book.toml",
-		"docs/css",
-		"docs/css/custom.css",
+		"docs/site",
+		"docs/site/book.toml",
+		"docs/site/css",
+		"docs/site/css/custom.css",
+		"docs/site/src",
+		"docs/src",
+		"docs/src/index.md",
 		"docs/test",
 	})
 }
 
-func TestMdBookRenderSummary(t *testing.T) {
+func TestMdBookWriteSummary(t *testing.T) {
 	f := MdBook{}
 
 	docs := document.Docs{
@@ -107,21 +112,83 @@ func TestMdBookRenderSummary(t *testing.T) {
 					MemberKind: document.MemberKind{Kind: "package"},
 				},
 			},
+			Functions: []*document.Function{
+				{
+					MemberName: document.MemberName{Name: "func"},
+					MemberKind: document.MemberKind{Kind: "function"},
+				},
+			},
 		},
 	}
 
+	dir := t.TempDir()
+	inDir := path.Join(dir, "in")
+	outDir := path.Join(dir, "out")
+	assert.Nil(t, os.MkdirAll(path.Join(inDir, "guide"), os.ModePerm))
+	assert.Nil(t, os.MkdirAll(outDir, os.ModePerm))
+	assert.Nil(t, os.WriteFile(path.Join(inDir, "index.md"), []byte("# Home\n"), 0644))
+	assert.Nil(t, os.WriteFile(path.Join(inDir, "guide", "usage.md"), []byte("Text\n\n# Usage\n"), 0644))
+	assert.Nil(t, os.WriteFile(path.Join(inDir, "pkg.json"), []byte("{}"), 0644))
+	assert.Nil(t, os.WriteFile(path.Join(outDir, "SUMMARY.md"),
+		[]byte("# Summary\n\n- [Old](old.md)\n\n# other\n\n- [`other`](other/_index.md)\n\n# pkg\n\n- [`pkg`](pkg/old.md)\n"), 0644))
+
 	templ, err := document.LoadTemplates(&f, "")
 	assert.Nil(t, err)
 
-	proc := document.NewProcessor(&docs, &f, templ, &document.Config{})
+	proc := document.NewProcessor(&docs, &f, templ, &document.Config{InputFiles: []string{inDir}, OutputDir: outDir})
+	proc.ExportDocs = &docs
+
+	err = f.WriteAuxiliary(docs.Decl, outDir, proc)
+	assert.Nil(t, err)
 
-	text, err := f.renderSummary(docs.Decl, proc)
+	content, err := os.ReadFile(path.Join(outDir, "SUMMARY.md"))
 	assert.Nil(t, err)
 
-	fmt.Println(text)
+	assert.Equal(t, "# Summary\n"+
+		"\n"+
+		"[Home](index.md)\n"+
+		"\n"+
+		"- [Usage](guide/usage.md)\n"+
+		"\n"+
+		"# other\n"+
+		"\n"+
+		"- [`other`](other/_index.md)\n"+
+		"\n"+
+		"# pkg\n"+
+		"\n"+
+		"- [`pkg`](pkg/_index.md)\n"+
+		"  - [`subpkg`](pkg/subpkg/_index.md)\n"+
+		"  - [`mod`](pkg/mod/_index.md)\n"+
+		"    - [`Struct`](pkg/mod/Struct.md)\n"+
+		"  - [`func`](pkg/func.md)\n"+
+		"\n", strings.ReplaceAll(string(content), "\r\n", "\n"))
+}
+
+func TestMdBookClean(t *testing.T) {
+	f := MdBook{}
+
+	outDir := t.TempDir()
+	for _, dir := range []string{"pkg/mod", "css", "guide"} {
+		assert.Nil(t, os.MkdirAll(path.Join(outDir, dir), os.ModePerm))
+	}
+	for _, file := range []string{"book.toml", "pkg.json", mdBookSummaryFile, "pkg/_index.md", "pkg/mod/_index.md", "css/custom.css", "guide/usage.md"} {
+		assert.Nil(t, os.WriteFile(path.Join(outDir, file), []byte{}, 0644))
+	}
 
-	assert.Contains(t, text, "[`pkg`](_index.md)")
-	assert.Contains(t, text, "- [`subpkg`](subpkg/_index.md))")
-	assert.Contains(t, text, "- [`mod`](mod/_index.md)")
-	assert.Contains(t, text, "  - [`Struct`](mod/Struct.md)")
+	assert.Nil(t, f.Clean(outDir, ""))
+
+	files, err := listDir(outDir)
+	assert.Nil(t, err)
+	root := strings.ReplaceAll(outDir, "\\", "/")
+	assert.Equal(t, []string{
+		root,
+		root + "/book.toml",
+		root + "/css",
+		root + "/css/custom.css",
+		root + "/guide",
+		root + "/guide/usage.md",
+		root + "/pkg.json",
+	}, files)
+
+	assert.Nil(t, f.Clean(path.Join(outDir, "missing"), ""))
 }
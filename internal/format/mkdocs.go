@@ -0,0 +1,281 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"text/template"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/mlange-42/modo/internal/util"
+	"gopkg.in/yaml.v3"
+)
+
+const landingPageContentMkDocs = `# Landing page
+
+JSON created by mojo doc should be placed next to this file.
+
+Additional documentation files go here, too.
+They will be processed for doc-tests and copied to folder 'site/docs'.
+`
+
+const mkDocsConfigFile = "mkdocs.yml"
+
+type MkDocs struct{}
+
+func (f *MkDocs) Accepts(files []string) error {
+	return nil
+}
+
+func (f *MkDocs) ProcessMarkdown(element any, text string, proc *document.Processor) (string, error) {
+	return text, nil
+}
+
+func (f *MkDocs) WriteAuxiliary(p *document.Package, dir string, proc *document.Processor) error {
+	if err := f.writeNav(p, dir, proc); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (f *MkDocs) ToFilePath(p string, kind string) string {
+	if kind == "package" || kind == "module" {
+		return path.Join(p, "index.md")
+	}
+	if len(p) == 0 {
+		return p
+	}
+	return p + ".md"
+}
+
+func (f *MkDocs) ToLinkPath(p string, kind string) string {
+	return f.ToFilePath(p, kind)
+}
+
+// ToURL converts a member path to the URL of its page, with MkDocs' default directory URLs.
+func (f *MkDocs) ToURL(p string, kind string) string {
+	return p + "/"
+}
+
+func (f *MkDocs) Input(in string, sources []document.PackageSource) string {
+	return in
+}
+
+func (f *MkDocs) Output(out string) string {
+	return path.Join(out, "docs")
+}
+
+func (f *MkDocs) GitIgnore(in, out string, sources []document.PackageSource) []string {
+	return []string{
+		"# files generated by 'mojo doc'",
+		fmt.Sprintf("/%s/*.json", in),
+		"# files generated by Modo",
+		fmt.Sprintf("/%s/%s/", out, "docs"),
+		"# files generated by MkDocs",
+		fmt.Sprintf("/%s/%s/", out, "public"),
+		"# test file generated by Modo",
+		"/test/",
+	}
+}
+
+func (f *MkDocs) CreateDirs(base, in, out string, sources []document.PackageSource, templ *template.Template) error {
+	inDir, outDir := path.Join(base, in), path.Join(base, f.Output(out))
+	testDir := path.Join(base, "test")
+	if err := util.MkDirs(inDir); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path.Join(inDir, "index.md"), []byte(landingPageContentMkDocs), 0644); err != nil {
+		return err
+	}
+	if err := util.MkDirs(outDir); err != nil {
+		return err
+	}
+	if err := util.MkDirs(testDir); err != nil {
+		return err
+	}
+	return f.createInitialFiles(base, path.Join(base, out), templ)
+}
+
+func (f *MkDocs) createInitialFiles(docDir, mkDocsDir string, templ *template.Template) error {
+	config, err := document.GetGitOrigin(docDir)
+	if err != nil {
+		return err
+	}
+
+	outFile := path.Join(mkDocsDir, mkDocsConfigFile)
+	exists, _, err := util.FileExists(outFile)
+	if err != nil {
+		return err
+	}
+	if exists {
+		fmt.Printf("WARNING: MkDocs config file %s already exists, skip creating\n", outFile)
+		return nil
+	}
+
+	b := bytes.Buffer{}
+	if err := templ.ExecuteTemplate(&b, mkDocsConfigFile, config); err != nil {
+		return err
+	}
+	return os.WriteFile(outFile, b.Bytes(), 0644)
+}
+
+func (f *MkDocs) Clean(out, tests string) error {
+	if err := emptyDir(out); err != nil {
+		return err
+	}
+	return document.CleanDocTests(tests)
+}
+
+// writeNav updates the package's entry in the 'nav' section of 'mkdocs.yml'.
+// The config file is expected in the parent directory of the output directory.
+func (f *MkDocs) writeNav(p *document.Package, dir string, proc *document.Processor) error {
+	outDir := filepath.Clean(proc.Config.OutputDir)
+	configFile := filepath.Join(filepath.Dir(outDir), mkDocsConfigFile)
+
+	exists, _, err := util.FileExists(configFile)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		fmt.Printf("WARNING: MkDocs config file %s not found, skip writing nav\n", configFile)
+		return nil
+	}
+
+	relDir, err := filepath.Rel(outDir, filepath.Clean(dir))
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+	nav := f.renderPackage(p, filepath.ToSlash(relDir))
+	content, err = f.updateNav(content, p.GetName(), nav)
+	if err != nil {
+		return fmt.Errorf("error updating nav in %s: %s", configFile, err.Error())
+	}
+
+	if proc.Config.DryRun {
+		return nil
+	}
+	return os.WriteFile(configFile, content, 0644)
+}
+
+// updateNav replaces or appends the nav entry with the given title.
+func (f *MkDocs) updateNav(config []byte, title string, entry *yaml.Node) ([]byte, error) {
+	doc := yaml.Node{}
+	if err := yaml.Unmarshal(config, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config is not a mapping")
+	}
+
+	var nav *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "nav" {
+			nav = root.Content[i+1]
+			break
+		}
+	}
+	if nav == nil {
+		nav = &yaml.Node{Kind: yaml.SequenceNode}
+		root.Content = append(root.Content, navScalar("nav"), nav)
+	} else if nav.Kind != yaml.SequenceNode {
+		*nav = yaml.Node{Kind: yaml.SequenceNode}
+	}
+
+	replaced := false
+	for i, item := range nav.Content {
+		if item.Kind == yaml.MappingNode && len(item.Content) == 2 && item.Content[0].Value == title {
+			nav.Content[i] = entry
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		nav.Content = append(nav.Content, entry)
+	}
+
+	b := bytes.Buffer{}
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (f *MkDocs) renderPackage(pkg *document.Package, linkPath string) *yaml.Node {
+	newPath := path.Join(linkPath, pkg.GetFileName())
+
+	children := []*yaml.Node{
+		navScalar(f.ToLinkPath(newPath, "package")),
+	}
+	for _, p := range pkg.Packages {
+		children = append(children, f.renderPackage(p, newPath))
+	}
+	for _, m := range pkg.Modules {
+		children = append(children, f.renderModule(m, newPath))
+	}
+	for _, elem := range pkg.Structs {
+		children = append(children, f.renderModuleMember(elem, newPath))
+	}
+	for _, elem := range pkg.Traits {
+		children = append(children, f.renderModuleMember(elem, newPath))
+	}
+	for _, elem := range pkg.Functions {
+		children = append(children, f.renderModuleMember(elem, newPath))
+	}
+	return navSection(pkg.GetName(), children)
+}
+
+func (f *MkDocs) renderModule(mod *document.Module, linkPath string) *yaml.Node {
+	newPath := path.Join(linkPath, mod.GetFileName())
+
+	children := []*yaml.Node{
+		navScalar(f.ToLinkPath(newPath, "module")),
+	}
+	for _, elem := range mod.Structs {
+		children = append(children, f.renderModuleMember(elem, newPath))
+	}
+	for _, elem := range mod.Traits {
+		children = append(children, f.renderModuleMember(elem, newPath))
+	}
+	for _, elem := range mod.Functions {
+		children = append(children, f.renderModuleMember(elem, newPath))
+	}
+	return navSection(mod.GetName(), children)
+}
+
+func (f *MkDocs) renderModuleMember(mem document.Named, linkPath string) *yaml.Node {
+	memPath := f.ToLinkPath(path.Join(linkPath, mem.GetFileName()), "")
+	return &yaml.Node{
+		Kind:    yaml.MappingNode,
+		Content: []*yaml.Node{navScalar(mem.GetName()), navScalar(memPath)},
+	}
+}
+
+func navScalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+}
+
+func navSection(title string, children []*yaml.Node) *yaml.Node {
+	return &yaml.Node{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			navScalar(title),
+			{Kind: yaml.SequenceNode, Content: children},
+		},
+	}
+}
@@ -0,0 +1,132 @@
+package format
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMkDocsToFilePath(t *testing.T) {
+	f := MkDocs{}
+
+	text := f.ToFilePath("pkg/mod/Struct", "struct")
+	assert.Equal(t, text, "pkg/mod/Struct.md")
+
+	text = f.ToFilePath("pkg/mod", "module")
+	assert.Equal(t, text, "pkg/mod/index.md")
+
+	text = f.ToFilePath("pkg", "package")
+	assert.Equal(t, text, "pkg/index.md")
+}
+
+func TestMkDocsToLinkPath(t *testing.T) {
+	f := MkDocs{}
+
+	text := f.ToLinkPath("pkg/mod/Struct", "struct")
+	assert.Equal(t, text, "pkg/mod/Struct.md")
+
+	text = f.ToLinkPath("pkg/mod", "module")
+	assert.Equal(t, text, "pkg/mod/index.md")
+
+	text = f.ToLinkPath("pkg", "package")
+	assert.Equal(t, text, "pkg/index.md")
+}
+
+func TestMkDocsInput(t *testing.T) {
+	f := MkDocs{}
+	assert.Equal(t, f.Input("src", []document.PackageSource{
+		{Name: "pkg", Path: []string{"src", "pkg"}},
+	}), "src")
+}
+
+func TestMkDocsOutput(t *testing.T) {
+	f := MkDocs{}
+	assert.Equal(t, f.Output("site"), "site/docs")
+}
+
+func TestMkDocsGitIgnore(t *testing.T) {
+	f := MkDocs{}
+	gi := f.GitIgnore("src", "site", []document.PackageSource{})
+
+	assert.Contains(t, gi, "/src/*.json")
+	assert.Contains(t, gi, "/site/docs/")
+	assert.Contains(t, gi, "/site/public/")
+	assert.Contains(t, gi, "/test/")
+}
+
+func TestMkDocsCreateDirs(t *testing.T) {
+	f := MkDocs{}
+	testCreateDirs(&f, t, "docs/site/docs", []string{
+		".",
+		"docs",
+		"docs/site",
+		"docs/site/docs",
+		"docs/site/mkdocs.yml",
+		"docs/src",
+		"docs/src/index.md",
+		"docs/test",
+	})
+}
+
+func TestMkDocsWriteNav(t *testing.T) {
+	f := MkDocs{}
+
+	docs := document.Docs{
+		Decl: &document.Package{
+			MemberName: document.MemberName{Name: "pkg"},
+			MemberKind: document.MemberKind{Kind: "package"},
+
+			Modules: []*document.Module{
+				{
+					MemberName: document.MemberName{Name: "mod"},
+					MemberKind: document.MemberKind{Kind: "module"},
+					Structs: []*document.Struct{
+						{
+							MemberName: document.MemberName{Name: "Struct"},
+							MemberKind: document.MemberKind{Kind: "struct"},
+						},
+					},
+				},
+			},
+			Packages: []*document.Package{
+				{
+					MemberName: document.MemberName{Name: "subpkg"},
+					MemberKind: document.MemberKind{Kind: "package"},
+				},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	outDir := path.Join(dir, "docs")
+	configFile := path.Join(dir, "mkdocs.yml")
+	err := os.WriteFile(configFile, []byte("site_name: test\nnav:\n  - Home: index.md\n  - pkg: old.md\n"), 0644)
+	assert.Nil(t, err)
+
+	templ, err := document.LoadTemplates(&f, "")
+	assert.Nil(t, err)
+	proc := document.NewProcessor(&docs, &f, templ, &document.Config{OutputDir: outDir})
+
+	err = f.WriteAuxiliary(docs.Decl, outDir, proc)
+	assert.Nil(t, err)
+
+	content, err := os.ReadFile(configFile)
+	assert.Nil(t, err)
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+
+	assert.Equal(t, `site_name: test
+nav:
+  - Home: index.md
+  - pkg:
+      - pkg/index.md
+      - subpkg:
+          - pkg/subpkg/index.md
+      - mod:
+          - pkg/mod/index.md
+          - Struct: pkg/mod/Struct.md
+`, text)
+}
@@ -0,0 +1,167 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/mlange-42/modo/internal/util"
+)
+
+// mystHeadingAnchors is the deepest heading level that gets an anchor in MyST,
+// as set by 'myst_heading_anchors' in the generated conf.py. Overload headings are level 4.
+const mystHeadingAnchors = 4
+
+var mystLinkRegex = regexp.MustCompile(`\[([^\]]*)\]\(([^)#\s:]+)\.md(#[^)\s]*)?\)`)
+
+type MyST struct{}
+
+func (f *MyST) Accepts(files []string) error {
+	return nil
+}
+
+// ProcessMarkdown appends a toctree directive to package and module pages.
+func (f *MyST) ProcessMarkdown(element any, text string, proc *document.Processor) (string, error) {
+	switch element.(type) {
+	case *document.Package, *document.Module:
+	default:
+		return text, nil
+	}
+	b := strings.Builder{}
+	b.WriteString(text)
+	if err := proc.Template.ExecuteTemplate(&b, "myst_toctree.md", element); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ProcessPage turns links to pages into MyST {doc} roles.
+func (f *MyST) ProcessPage(text string, dir string, proc *document.Processor) (string, error) {
+	return f.ProcessLinks(text, dir, proc)
+}
+
+// ProcessLinks turns links to pages into MyST {doc} roles.
+// Links to sections are kept as Markdown links, as {doc} roles don't support anchors.
+func (f *MyST) ProcessLinks(text string, dir string, proc *document.Processor) (string, error) {
+	return mystLinkRegex.ReplaceAllStringFunc(text, func(link string) string {
+		parts := mystLinkRegex.FindStringSubmatch(link)
+		if parts[3] != "" {
+			return link
+		}
+		linkText := strings.ReplaceAll(strings.ReplaceAll(parts[1], "`", ""), "<", `\<`)
+		return fmt.Sprintf("{doc}`%s <%s>`", linkText, parts[2])
+	}), nil
+}
+
+// WriteAuxiliary writes the root index.md that includes all packages.
+func (f *MyST) WriteAuxiliary(p *document.Package, dir string, proc *document.Processor) error {
+	if proc.Config.DryRun {
+		return nil
+	}
+	b := bytes.Buffer{}
+	if err := proc.Template.ExecuteTemplate(&b, "myst_index.md", p); err != nil {
+		return err
+	}
+	return os.WriteFile(path.Join(dir, "index.md"), b.Bytes(), 0644)
+}
+
+func (f *MyST) ToFilePath(p string, kind string) string {
+	if kind == "package" || kind == "module" {
+		return path.Join(p, "index.md")
+	}
+	if len(p) == 0 {
+		return p
+	}
+	return p + ".md"
+}
+
+func (f *MyST) ToLinkPath(p string, kind string) string {
+	return f.ToFilePath(p, kind)
+}
+
+// ToURL converts a member path to the URL of the HTML page rendered by Sphinx.
+func (f *MyST) ToURL(p string, kind string) string {
+	return strings.TrimSuffix(f.ToFilePath(p, kind), ".md") + ".html"
+}
+
+func (f *MyST) Input(in string, sources []document.PackageSource) string {
+	return in
+}
+
+func (f *MyST) Output(out string) string {
+	return path.Join(out, "api")
+}
+
+func (f *MyST) GitIgnore(in, out string, sources []document.PackageSource) []string {
+	return []string{
+		"# files generated by 'mojo doc'",
+		fmt.Sprintf("/%s/*.json", in),
+		"# files generated by Modo",
+		fmt.Sprintf("/%s/%s/", out, "api"),
+		"# files generated by Sphinx",
+		fmt.Sprintf("/%s/%s/", out, "_build"),
+		"# test file generated by Modo",
+		"/test/",
+	}
+}
+
+func (f *MyST) CreateDirs(base, in, out string, sources []document.PackageSource, templ *template.Template) error {
+	inDir, outDir := path.Join(base, in), path.Join(base, f.Output(out))
+	testDir := path.Join(base, "test")
+	if err := util.MkDirs(inDir); err != nil {
+		return err
+	}
+	if err := util.MkDirs(outDir); err != nil {
+		return err
+	}
+	if err := util.MkDirs(testDir); err != nil {
+		return err
+	}
+	return f.createInitialFiles(base, path.Join(base, out), templ)
+}
+
+func (f *MyST) createInitialFiles(docDir, sphinxDir string, templ *template.Template) error {
+	config, err := document.GetGitOrigin(docDir)
+	if err != nil {
+		return err
+	}
+
+	files := [][]string{
+		{"myst.conf.py", "conf.py"},
+		{"myst.index.md", "index.md"},
+	}
+	for _, f := range files {
+		outFile := path.Join(sphinxDir, f[1])
+		exists, _, err := util.FileExists(outFile)
+		if err != nil {
+			return err
+		}
+		if exists {
+			fmt.Printf("WARNING: Sphinx file %s already exists, skip creating\n", outFile)
+			return nil
+		}
+	}
+
+	for _, f := range files {
+		b := bytes.Buffer{}
+		if err := templ.ExecuteTemplate(&b, f[0], config); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path.Join(sphinxDir, f[1]), b.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *MyST) Clean(out, tests string) error {
+	if err := emptyDir(out); err != nil {
+		return err
+	}
+	return document.CleanDocTests(tests)
+}
@@ -0,0 +1,137 @@
+package format
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMySTToFilePath(t *testing.T) {
+	f := MyST{}
+
+	text := f.ToFilePath("pkg/mod/Struct", "struct")
+	assert.Equal(t, text, "pkg/mod/Struct.md")
+
+	text = f.ToFilePath("pkg/mod", "module")
+	assert.Equal(t, text, "pkg/mod/index.md")
+
+	text = f.ToFilePath("pkg", "package")
+	assert.Equal(t, text, "pkg/index.md")
+}
+
+func TestMySTProcessMarkdown(t *testing.T) {
+	form := MyST{}
+	templ, err := document.LoadTemplates(&form, "")
+	assert.Nil(t, err)
+
+	proc := document.NewProcessor(nil, &form, templ, &document.Config{})
+
+	text, err := form.ProcessMarkdown(&document.Package{
+		MemberName: document.MemberName{Name: "pkg"},
+		MemberKind: document.MemberKind{Kind: "package"},
+		Packages: []*document.Package{
+			{MemberName: document.MemberName{Name: "subpkg"}, MemberKind: document.MemberKind{Kind: "package"}},
+		},
+		Modules: []*document.Module{
+			{MemberName: document.MemberName{Name: "mod"}, MemberKind: document.MemberKind{Kind: "module"}},
+		},
+		Functions: []*document.Function{
+			{MemberName: document.MemberName{Name: "func"}, MemberKind: document.MemberKind{Kind: "function"}},
+		},
+	}, "# `pkg`\n", proc)
+	assert.Nil(t, err)
+
+	assert.Equal(t,
+		"# `pkg`\n"+
+			"\n"+
+			"```{toctree}\n"+
+			":hidden:\n"+
+			":maxdepth: 1\n"+
+			"\n"+
+			"subpkg/index\n"+
+			"mod/index\n"+
+			"func\n"+
+			"```\n",
+		strings.ReplaceAll(text, "\r\n", "\n"))
+
+	text, err = form.ProcessMarkdown(&document.Module{
+		MemberName: document.MemberName{Name: "mod"},
+		MemberKind: document.MemberKind{Kind: "module"},
+	}, "# `mod`\n", proc)
+	assert.Nil(t, err)
+	assert.Equal(t, "# `mod`\n", text)
+
+	text, err = form.ProcessMarkdown(&document.Struct{
+		MemberName: document.MemberName{Name: "Struct"},
+		MemberKind: document.MemberKind{Kind: "struct"},
+	}, "# `Struct`\n", proc)
+	assert.Nil(t, err)
+	assert.Equal(t, "# `Struct`\n", text)
+}
+
+func TestMySTProcessPage(t *testing.T) {
+	form := MyST{}
+	proc := document.NewProcessor(nil, &form, nil, &document.Config{})
+
+	text, err := form.ProcessPage(
+		"A [`Struct`](Struct.md), a [`Struct.__init__`](Struct.md#__init__), "+
+			"the [`pkg`](../index.md), a [`List<T>`](../List.md) and a [Markdown](https://example.com/page.md).",
+		"pkg/mod", proc)
+	assert.Nil(t, err)
+
+	assert.Equal(t,
+		"A {doc}`Struct <Struct>`, a [`Struct.__init__`](Struct.md#__init__), "+
+			"the {doc}`pkg <../index>`, a {doc}`List\\<T> <../List>` and a [Markdown](https://example.com/page.md).",
+		text)
+}
+
+func TestMySTOutput(t *testing.T) {
+	f := MyST{}
+	assert.Equal(t, f.Output("site"), "site/api")
+}
+
+func TestMySTGitIgnore(t *testing.T) {
+	f := MyST{}
+	gi := f.GitIgnore("src", "site", []document.PackageSource{})
+
+	assert.Contains(t, gi, "/src/*.json")
+	assert.Contains(t, gi, "/site/api/")
+	assert.Contains(t, gi, "/site/_build/")
+	assert.Contains(t, gi, "/test/")
+}
+
+func TestMySTCreateDirs(t *testing.T) {
+	f := MyST{}
+	testCreateDirs(&f, t, "docs/site/api", []string{
+		".",
+		"docs",
+		"docs/site",
+		"docs/site/api",
+		"docs/site/conf.py",
+		"docs/site/index.md",
+		"docs/src",
+		"docs/test",
+	})
+}
+
+func TestMySTWriteAuxiliary(t *testing.T) {
+	f := MyST{}
+	templ, err := document.LoadTemplates(&f, "")
+	assert.Nil(t, err)
+
+	dir := t.TempDir()
+	proc := document.NewProcessor(nil, &f, templ, &document.Config{OutputDir: dir})
+	err = f.WriteAuxiliary(&document.Package{
+		MemberName: document.MemberName{Name: "pkg"},
+		MemberKind: document.MemberKind{Kind: "package"},
+	}, dir, proc)
+	assert.Nil(t, err)
+
+	content, err := os.ReadFile(path.Join(dir, "index.md"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "```{toctree}\n:glob:\n:maxdepth: 1\n\n*/index\n```\n")
+}
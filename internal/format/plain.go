@@ -84,5 +84,5 @@ func (f *Plain) Clean(out, tests string) error {
 	if err := emptyDir(out); err != nil {
 		return err
 	}
-	return emptyDir(tests)
+	return document.CleanDocTests(tests)
 }
@@ -0,0 +1,281 @@
+package format
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/mlange-42/modo/internal/document"
+)
+
+// pluginProtocolVersion is the version of the JSON protocol spoken with formatter plugins.
+const pluginProtocolVersion = 1
+
+// pluginTimeout is the time to wait for a response of a formatter plugin.
+const pluginTimeout = 60 * time.Second
+
+// Plugin is a formatter that delegates to an external executable.
+//
+// The executable is started once per build and kept running until [Plugin.Close].
+// Modo sends one JSON request per line to its STDIN,
+// and the executable answers each request with one JSON response line on STDOUT.
+// See the documentation of output formats for the protocol.
+//
+// Functionality not covered by the protocol is the same as for the plain Markdown format.
+type Plugin struct {
+	Plain
+	command string
+	args    []string
+	mutex   sync.Mutex
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	stdout  *bufio.Reader
+	timeout time.Duration
+	err     error // First error in a method that can't return it.
+	failed  error // Error that left the plugin process unusable.
+}
+
+type pluginRequest struct {
+	Version int    `json:"version"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type pluginInitResult struct {
+	Version int `json:"version"`
+}
+
+type pluginResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  string          `json:"error"`
+}
+
+type pluginPathParams struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+}
+
+type pluginMarkdownParams struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Text string `json:"text"`
+}
+
+type pluginAuxiliaryParams struct {
+	Dir     string            `json:"dir"`
+	DryRun  bool              `json:"dryRun"`
+	Package *document.Package `json:"package"`
+}
+
+// newPlugin creates a formatter plugin for the given executable.
+func newPlugin(command string, args ...string) *Plugin {
+	return &Plugin{command: command, args: args, timeout: pluginTimeout}
+}
+
+// ProcessMarkdown lets the plugin alter the final content of the markdown output.
+// It also reports errors from previous calls of methods that can't return an error.
+func (f *Plugin) ProcessMarkdown(element any, text string, proc *document.Processor) (string, error) {
+	if err := f.firstError(); err != nil {
+		return "", err
+	}
+	params := pluginMarkdownParams{Text: text}
+	if e, ok := element.(document.Kinded); ok {
+		params.Kind = e.GetKind()
+	}
+	if e, ok := element.(document.Named); ok {
+		params.Name = e.GetName()
+	}
+	var result string
+	if err := f.call("processMarkdown", &params, &result); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// WriteAuxiliary lets the plugin write auxiliary files.
+// It also reports errors from previous calls of methods that can't return an error.
+func (f *Plugin) WriteAuxiliary(p *document.Package, dir string, proc *document.Processor) error {
+	if err := f.firstError(); err != nil {
+		return err
+	}
+	return f.call("writeAuxiliary", &pluginAuxiliaryParams{Dir: dir, DryRun: proc.Config.DryRun, Package: p}, nil)
+}
+
+// ToFilePath asks the plugin to convert a member path to a file path.
+// Errors are printed immediately, and returned by the next call of a method that can return an error.
+func (f *Plugin) ToFilePath(p string, kind string) string {
+	var result string
+	if err := f.call("toFilePath", &pluginPathParams{Path: p, Kind: kind}, &result); err != nil {
+		f.setError(err)
+		return ""
+	}
+	return result
+}
+
+// ToLinkPath asks the plugin to convert a member path to a link.
+// Errors are printed immediately, and returned by the next call of a method that can return an error.
+func (f *Plugin) ToLinkPath(p string, kind string) string {
+	var result string
+	if err := f.call("toLinkPath", &pluginPathParams{Path: p, Kind: kind}, &result); err != nil {
+		f.setError(err)
+		return ""
+	}
+	return result
+}
+
+// Close closes the plugin's STDIN and waits for the process to exit.
+// The plugin is started again on the next request.
+func (f *Plugin) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	cmd, stdin, failed := f.cmd, f.stdin, f.failed
+	f.cmd, f.stdin, f.stdout, f.failed = nil, nil, nil, nil
+	if cmd == nil {
+		return nil
+	}
+	_ = stdin.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(f.timeout):
+		_ = cmd.Process.Kill()
+		<-done
+		err = fmt.Errorf("no exit within %s", f.timeout)
+	}
+	if err != nil && failed == nil {
+		return fmt.Errorf("error stopping formatter plugin '%s': %s", f.command, err.Error())
+	}
+	return nil
+}
+
+// call sends a request to the plugin and decodes the result into result, if it is not nil.
+func (f *Plugin) call(method string, params any, result any) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.start(); err != nil {
+		return err
+	}
+	return f.request(method, params, result)
+}
+
+// request sends a request to the running plugin and decodes the result into result, if it is not nil.
+// Fails if the plugin does not respond in time.
+func (f *Plugin) request(method string, params any, result any) error {
+	if f.failed != nil {
+		return f.failed
+	}
+	request, err := json.Marshal(&pluginRequest{Version: pluginProtocolVersion, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if _, err := f.stdin.Write(append(request, '\n')); err != nil {
+		return f.fail(fmt.Errorf("error sending request '%s' to formatter plugin '%s': %s", method, f.command, err.Error()))
+	}
+
+	line, err := f.readLine()
+	if err != nil {
+		return f.fail(fmt.Errorf("error reading response to '%s' from formatter plugin '%s': %s", method, f.command, err.Error()))
+	}
+	response := pluginResponse{}
+	if err := json.Unmarshal(line, &response); err != nil {
+		return fmt.Errorf("invalid response to '%s' from formatter plugin '%s': %s", method, f.command, err.Error())
+	}
+	if response.Error != "" {
+		return fmt.Errorf("formatter plugin '%s' failed on '%s': %s", f.command, method, response.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("invalid result for '%s' from formatter plugin '%s': %s", method, f.command, err.Error())
+	}
+	return nil
+}
+
+// readLine reads a response line from the plugin, with a timeout.
+func (f *Plugin) readLine() ([]byte, error) {
+	type response struct {
+		line []byte
+		err  error
+	}
+	stdout := f.stdout
+	ch := make(chan response, 1)
+	go func() {
+		line, err := stdout.ReadBytes('\n')
+		ch <- response{line, err}
+	}()
+	select {
+	case r := <-ch:
+		return r.line, r.err
+	case <-time.After(f.timeout):
+		return nil, fmt.Errorf("no response within %s", f.timeout)
+	}
+}
+
+// fail marks the plugin process as unusable and stops it.
+// Subsequent requests fail with the given error, until the plugin is closed.
+func (f *Plugin) fail(err error) error {
+	f.failed = err
+	if f.cmd != nil {
+		_ = f.cmd.Process.Kill()
+	}
+	return err
+}
+
+// start starts the plugin process, if not already running, and checks the protocol version.
+func (f *Plugin) start() error {
+	if f.cmd != nil {
+		return nil
+	}
+	cmd := exec.Command(f.command, f.args...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error starting formatter plugin '%s': %s", f.command, err.Error())
+	}
+	f.cmd, f.stdin, f.stdout = cmd, stdin, bufio.NewReader(stdout)
+
+	result := pluginInitResult{}
+	if err := f.request("init", nil, &result); err != nil {
+		return f.fail(err)
+	}
+	if result.Version != pluginProtocolVersion {
+		return f.fail(fmt.Errorf("formatter plugin '%s' uses protocol version %d, but version %d is required",
+			f.command, result.Version, pluginProtocolVersion))
+	}
+	return nil
+}
+
+func (f *Plugin) setError(err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.err == nil {
+		fmt.Printf("ERROR: %s\n", err.Error())
+		f.err = err
+	}
+}
+
+func (f *Plugin) firstError() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	err := f.err
+	f.err = nil
+	return err
+}
@@ -0,0 +1,144 @@
+package format
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/stretchr/testify/assert"
+)
+
+const pluginTestEnv = "MODO_TEST_FORMATTER_PLUGIN"
+
+// TestMain lets the test binary act as a formatter plugin, if started by the tests.
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(pluginTestEnv); mode != "" {
+		runTestPlugin(mode)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runTestPlugin is a minimal formatter plugin, used to test the protocol.
+// With mode "version", it reports an unsupported protocol version.
+// With mode "hang", it never answers requests other than the handshake.
+func runTestPlugin(mode string) {
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		request := struct {
+			Version int
+			Method  string
+			Params  map[string]any
+		}{}
+		response := map[string]any{}
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			response["error"] = err.Error()
+		} else {
+			switch request.Method {
+			case "init":
+				response["result"] = map[string]any{"version": 1}
+				if mode == "version" {
+					response["result"] = map[string]any{"version": 99}
+				}
+			case "toFilePath":
+				response["result"] = fmt.Sprintf("%s.%s.txt", request.Params["path"], request.Params["kind"])
+			case "toLinkPath":
+				response["result"] = fmt.Sprintf("/%s", request.Params["path"])
+			case "processMarkdown":
+				response["result"] = fmt.Sprintf("%s %s\n%s", request.Params["kind"], request.Params["name"], request.Params["text"])
+			case "writeAuxiliary":
+				pkg := request.Params["package"].(map[string]any)
+				if pkg["Name"] != "pkg" {
+					response["error"] = "unexpected package"
+				}
+			default:
+				response["error"] = fmt.Sprintf("unknown method '%s'", request.Method)
+			}
+		}
+		if mode == "hang" && request.Method != "init" {
+			select {}
+		}
+		out, _ := json.Marshal(response)
+		fmt.Println(string(out))
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	t.Setenv(pluginTestEnv, "1")
+	f := newPlugin(os.Args[0])
+
+	assert.Equal(t, "pkg/mod/Struct.struct.txt", f.ToFilePath("pkg/mod/Struct", "struct"))
+	assert.Equal(t, "/pkg/mod/Struct", f.ToLinkPath("pkg/mod/Struct", "struct"))
+
+	text, err := f.ProcessMarkdown(&document.Struct{
+		MemberName: document.MemberName{Name: "Struct"},
+		MemberKind: document.MemberKind{Kind: "struct"},
+	}, "# `Struct`\n", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "struct Struct\n# `Struct`\n", text)
+
+	proc := document.NewProcessor(nil, f, nil, &document.Config{})
+	err = f.WriteAuxiliary(&document.Package{
+		MemberName: document.MemberName{Name: "pkg"},
+		MemberKind: document.MemberKind{Kind: "package"},
+	}, "out", proc)
+	assert.Nil(t, err)
+
+	err = f.WriteAuxiliary(&document.Package{
+		MemberName: document.MemberName{Name: "other"},
+		MemberKind: document.MemberKind{Kind: "package"},
+	}, "out", proc)
+	assert.NotNil(t, err)
+	assert.True(t, strings.HasSuffix(err.Error(), "failed on 'writeAuxiliary': unexpected package"))
+
+	assert.Nil(t, f.Close())
+	assert.Nil(t, f.cmd)
+	assert.Nil(t, f.Close())
+
+	// Restarts after closing.
+	assert.Equal(t, "/pkg/mod/Struct", f.ToLinkPath("pkg/mod/Struct", "struct"))
+	assert.Nil(t, f.Close())
+}
+
+func TestPluginVersion(t *testing.T) {
+	t.Setenv(pluginTestEnv, "version")
+	f := newPlugin(os.Args[0])
+
+	assert.Equal(t, "", f.ToFilePath("pkg/mod/Struct", "struct"))
+	_, err := f.ProcessMarkdown(&document.Struct{}, "", nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "uses protocol version 99, but version 1 is required")
+	assert.Nil(t, f.Close())
+}
+
+func TestPluginTimeout(t *testing.T) {
+	t.Setenv(pluginTestEnv, "hang")
+	f := newPlugin(os.Args[0])
+	f.timeout = 100 * time.Millisecond
+
+	_, err := f.ProcessMarkdown(&document.Struct{}, "", nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "no response within 100ms")
+
+	_, err = f.ProcessMarkdown(&document.Struct{}, "", nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "no response within 100ms")
+	assert.Nil(t, f.Close())
+}
+
+func TestPluginNotFound(t *testing.T) {
+	f := newPlugin("modo-plugin-that-does-not-exist")
+
+	assert.Equal(t, "", f.ToFilePath("pkg/mod/Struct", "struct"))
+
+	proc := document.NewProcessor(nil, f, nil, &document.Config{})
+	err := f.WriteAuxiliary(&document.Package{}, "out", proc)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error starting formatter plugin")
+}
@@ -0,0 +1,131 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markdownToRoff converts Markdown to the body of a man page in roff format.
+// It supports the same subset of Markdown as [markdownToHTML].
+// Top-level and second-level headings become sections, deeper headings become sub-sections.
+func markdownToRoff(text string) string {
+	return renderMarkdown(text, roffRenderer{})
+}
+
+// roffRenderer renders Markdown to roff.
+// Code spans and strong emphasis become bold, emphasis becomes italic.
+// Links are rendered as their text, followed by the URL for external links.
+type roffRenderer struct{}
+
+func (r roffRenderer) paragraph(b *strings.Builder, text string) {
+	fmt.Fprintf(b, ".PP\n%s\n", roffLines(renderInline(text, r)))
+}
+
+func (r roffRenderer) heading(b *strings.Builder, level int, text string) {
+	macro := ".SH"
+	if level > 2 {
+		macro = ".SS"
+	}
+	fmt.Fprintf(b, "%s \"%s\"\n", macro, strings.ReplaceAll(renderInline(text, r), `"`, `\(dq`))
+}
+
+func (r roffRenderer) rule(b *strings.Builder) {}
+
+func (r roffRenderer) codeBlock(b *strings.Builder, lang string, lines []string) {
+	b.WriteString(".PP\n.RS 4\n.nf\n")
+	for _, line := range lines {
+		b.WriteString(roffLines(roffEscape(line)))
+		b.WriteRune('\n')
+	}
+	b.WriteString(".fi\n.RE\n")
+}
+
+func (r roffRenderer) blockQuote(b *strings.Builder, text string) {
+	fmt.Fprintf(b, ".RS 4\n%s.RE\n", renderMarkdown(text, r))
+}
+
+func (r roffRenderer) list(b *strings.Builder, ordered bool, items []string) {
+	for n, item := range items {
+		bullet, width := `\(bu`, 2
+		if ordered {
+			bullet, width = fmt.Sprintf("%d.", n+1), 4
+		}
+		fmt.Fprintf(b, ".IP %s %d\n", bullet, width)
+		b.WriteString(strings.TrimPrefix(renderMarkdown(item, r), ".PP\n"))
+	}
+}
+
+// table renders a table as pre-formatted text with aligned columns.
+func (r roffRenderer) table(b *strings.Builder, header []string, align []string, rows [][]string) {
+	rows = append([][]string{header}, rows...)
+	widths := []int{}
+	for i, row := range rows {
+		for c, cell := range row {
+			cell = plainInline(cell)
+			rows[i][c] = cell
+			if c >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[c] = max(widths[c], len(cell))
+		}
+	}
+
+	b.WriteString(".PP\n.RS 4\n.nf\n")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for c, cell := range row {
+			cells[c] = fmt.Sprintf("%-*s", widths[c], cell)
+		}
+		b.WriteString(roffLines(roffEscape(strings.TrimRight(strings.Join(cells, "  "), " "))))
+		b.WriteRune('\n')
+	}
+	b.WriteString(".fi\n.RE\n")
+}
+
+func (r roffRenderer) text(s string) string {
+	return roffEscape(s)
+}
+
+func (r roffRenderer) code(s string) string {
+	return fmt.Sprintf(`\fB%s\fR`, roffEscape(s))
+}
+
+func (r roffRenderer) link(text string, url string, image bool) string {
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		return fmt.Sprintf("%s <%s>", renderInline(text, r), roffEscape(url))
+	}
+	return renderInline(text, r)
+}
+
+func (r roffRenderer) autolink(url string) string {
+	return fmt.Sprintf("<%s>", roffEscape(url))
+}
+
+func (r roffRenderer) emphasis(text string, strong bool) string {
+	if strong {
+		return fmt.Sprintf(`\fB%s\fR`, text)
+	}
+	return fmt.Sprintf(`\fI%s\fR`, text)
+}
+
+// plainInline strips inline Markdown markup from a text.
+func plainInline(s string) string {
+	r := strings.NewReplacer(`\fB`, "", `\fI`, "", `\fR`, "", `\e`, `\`)
+	return r.Replace(renderInline(s, roffRenderer{}))
+}
+
+// roffEscape escapes backslashes for roff.
+func roffEscape(s string) string {
+	return strings.ReplaceAll(s, `\`, `\e`)
+}
+
+// roffLines protects lines starting with a control character.
+func roffLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,68 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownToRoff(t *testing.T) {
+	text := markdownToRoff("# `Struct`\n" +
+		"\n" +
+		"Some `code`, *emphasis* and **strong**\n" +
+		"text. A \\ backslash.\n" +
+		"\n" +
+		"### Method\n" +
+		"\n" +
+		"- item one\n" +
+		"- item two\n" +
+		"\n" +
+		"1. first\n" +
+		"2. second\n" +
+		"\n" +
+		"```mojo\n" +
+		"var x = 1\n" +
+		".dot\n" +
+		"```\n" +
+		"\n" +
+		"> quote\n" +
+		"\n" +
+		"| A | B |\n" +
+		"|---|---|\n" +
+		"| `x` | long cell |\n")
+
+	assert.Equal(t, ".SH \"\\fBStruct\\fR\"\n"+
+		".PP\n"+
+		"Some \\fBcode\\fR, \\fIemphasis\\fR and \\fBstrong\\fR\n"+
+		"text. A \\e backslash.\n"+
+		".SS \"Method\"\n"+
+		".IP \\(bu 2\n"+
+		"item one\n"+
+		".IP \\(bu 2\n"+
+		"item two\n"+
+		".IP 1. 4\n"+
+		"first\n"+
+		".IP 2. 4\n"+
+		"second\n"+
+		".PP\n"+
+		".RS 4\n"+
+		".nf\n"+
+		"var x = 1\n"+
+		"\\&.dot\n"+
+		".fi\n"+
+		".RE\n"+
+		".RS 4\n"+
+		".PP\n"+
+		"quote\n"+
+		".RE\n"+
+		".PP\n"+
+		".RS 4\n"+
+		".nf\n"+
+		"A  B\n"+
+		"x  long cell\n"+
+		".fi\n"+
+		".RE\n", text)
+
+	text = markdownToRoff("Some __strong__ and _emphasis_, a [link](javascript:alert(1)) and snake_case.")
+	assert.Equal(t, ".PP\nSome \\fBstrong\\fR and \\fIemphasis\\fR, a link and snake_case.\n", text)
+}
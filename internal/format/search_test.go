@@ -0,0 +1,103 @@
+package format_test
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/mlange-42/modo/internal/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchIndexURLs(t *testing.T) {
+	yml := `
+decl:
+  name: pkg
+  kind: package
+  modules:
+    - name: mod
+      kind: module
+      structs:
+        - name: Struct
+          kind: struct
+          functions:
+            - name: method
+              kind: function
+              overloads:
+                - name: method
+                  kind: function
+            - name: __init__
+              kind: function
+              overloads:
+                - name: __init__
+                  kind: function
+    - name: snake_mod
+      kind: module
+      functions:
+        - name: snake_func
+          kind: function
+          overloads:
+            - name: snake_func
+              kind: function
+`
+	tests := []struct {
+		Format string
+		Links  map[string]string
+	}{
+		{"hugo", map[string]string{
+			"pkg":                      "pkg/",
+			"pkg.mod":                  "pkg/mod/",
+			"pkg.mod.Struct":           "pkg/mod/struct/",
+			"pkg.mod.Struct.method":    "pkg/mod/struct/#method",
+			"pkg.mod.Struct.__init__":  "pkg/mod/struct/#__init__",
+			"pkg.snake_mod":            "pkg/snake_mod/",
+			"pkg.snake_mod.snake_func": "pkg/snake_mod/snake_func/",
+		}},
+		{"zola", map[string]string{
+			"pkg":                      "pkg/",
+			"pkg.mod":                  "pkg/mod/",
+			"pkg.mod.Struct":           "pkg/mod/struct/",
+			"pkg.mod.Struct.method":    "pkg/mod/struct/#method",
+			"pkg.mod.Struct.__init__":  "pkg/mod/struct/#init",
+			"pkg.snake_mod":            "pkg/snake-mod/",
+			"pkg.snake_mod.snake_func": "pkg/snake-mod/snake-func/",
+		}},
+		{"plain", map[string]string{
+			"pkg":                      "pkg/_index.md",
+			"pkg.mod":                  "pkg/mod/_index.md",
+			"pkg.mod.Struct":           "pkg/mod/Struct.md",
+			"pkg.mod.Struct.method":    "pkg/mod/Struct.md#method",
+			"pkg.mod.Struct.__init__":  "pkg/mod/Struct.md#__init__",
+			"pkg.snake_mod":            "pkg/snake_mod/_index.md",
+			"pkg.snake_mod.snake_func": "pkg/snake_mod/snake_func.md",
+		}},
+	}
+
+	for _, tt := range tests {
+		docs, err := document.FromYAML([]byte(yml))
+		assert.Nil(t, err)
+
+		form, err := format.GetFormatter(tt.Format)
+		assert.Nil(t, err)
+
+		outDir := t.TempDir()
+		config := document.Config{OutputDir: outDir, SearchIndex: true}
+		assert.Nil(t, document.Render(docs, &config, form, ""))
+
+		content, err := os.ReadFile(path.Join(outDir, "search-index.json"))
+		assert.Nil(t, err)
+		entries := []struct {
+			Path string `json:"path"`
+			Link string `json:"link"`
+		}{}
+		assert.Nil(t, json.Unmarshal(content, &entries))
+
+		links := map[string]string{}
+		for _, e := range entries {
+			links[e.Path] = e.Link
+		}
+		assert.Equal(t, tt.Links, links, tt.Format)
+	}
+}
@@ -0,0 +1,241 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/mlange-42/modo/internal/util"
+)
+
+const wikiLinkPrefix = "wiki:"
+const wikiSidebarFile = "_Sidebar.md"
+const wikiHomeFile = "Home.md"
+
+var wikiLinkRegex = regexp.MustCompile(`\]\(wiki:([^)#\s]*)(#[^)\s]*)?\)`)
+var wikiPartRegex = regexp.MustCompile("^### \\[`(.*)`\\]\\((.*)\\)$")
+
+type Wiki struct{}
+
+type wikiPart struct {
+	Title   string
+	Link    string
+	Content string
+}
+
+func (f *Wiki) Accepts(files []string) error {
+	return nil
+}
+
+func (f *Wiki) ProcessMarkdown(element any, text string, proc *document.Processor) (string, error) {
+	return text, nil
+}
+
+// ProcessPage turns relative internal links into links to flattened wiki page names.
+func (f *Wiki) ProcessPage(text string, dir string, proc *document.Processor) (string, error) {
+	return f.ProcessLinks(text, dir, proc)
+}
+
+// ProcessLinks turns relative internal links into links to flattened wiki page names.
+func (f *Wiki) ProcessLinks(text string, dir string, proc *document.Processor) (string, error) {
+	baseDir := path.Dir(proc.PackageDir())
+	return wikiLinkRegex.ReplaceAllStringFunc(text, func(link string) string {
+		parts := wikiLinkRegex.FindStringSubmatch(link)
+		return fmt.Sprintf("](%s%s)", flatLinkTarget(dir, parts[1], baseDir), parts[2])
+	}), nil
+}
+
+// WriteAuxiliary writes the wiki's sidebar and home page.
+// Entries of other packages, from previously processed JSON files, are retained.
+func (f *Wiki) WriteAuxiliary(p *document.Package, dir string, proc *document.Processor) error {
+	sidebarPath := path.Join(dir, wikiSidebarFile)
+
+	var parts []wikiPart
+	if content, err := os.ReadFile(sidebarPath); err == nil {
+		parts = parseWikiParts(string(content))
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	b := strings.Builder{}
+	f.renderPackage(p, nil, 0, &b)
+	part := wikiPart{Title: p.GetName(), Link: flatPageName(p.GetFileName()), Content: b.String()}
+	if idx := slices.IndexFunc(parts, func(s wikiPart) bool { return s.Title == part.Title }); idx >= 0 {
+		parts[idx] = part
+	} else {
+		parts = append(parts, part)
+	}
+
+	sidebar := strings.Builder{}
+	if err := proc.Template.ExecuteTemplate(&sidebar, "wiki_sidebar.md", parts); err != nil {
+		return err
+	}
+	home := strings.Builder{}
+	if err := proc.Template.ExecuteTemplate(&home, "wiki_home.md", parts); err != nil {
+		return err
+	}
+
+	if proc.Config.DryRun {
+		return nil
+	}
+	if err := os.WriteFile(sidebarPath, []byte(sidebar.String()), 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(path.Join(dir, wikiHomeFile), []byte(home.String()), 0644)
+}
+
+func (f *Wiki) renderPackage(pkg *document.Package, linkPath []string, depth int, out io.Writer) {
+	newPath := appendPath(linkPath, pkg.GetFileName())
+	if depth > 0 {
+		fmt.Fprintf(out, "%-*s- [`%s`](%s)\n", 2*(depth-1), "", pkg.GetName(), flatPageName(path.Join(newPath...)))
+	}
+	for _, p := range pkg.Packages {
+		f.renderPackage(p, newPath, depth+1, out)
+	}
+	for _, m := range pkg.Modules {
+		modPath := appendPath(newPath, m.GetFileName())
+		fmt.Fprintf(out, "%-*s- [`%s`](%s)\n", 2*depth, "", m.GetName(), flatPageName(path.Join(modPath...)))
+		f.renderMembers(m.Structs, m.Traits, m.Functions, modPath, depth+1, out)
+	}
+	f.renderMembers(pkg.Structs, pkg.Traits, pkg.Functions, newPath, depth, out)
+}
+
+func (f *Wiki) renderMembers(structs []*document.Struct, traits []*document.Trait, functions []*document.Function, linkPath []string, depth int, out io.Writer) {
+	members := []document.Named{}
+	for _, s := range structs {
+		members = append(members, s)
+	}
+	for _, t := range traits {
+		members = append(members, t)
+	}
+	for _, fn := range functions {
+		members = append(members, fn)
+	}
+	for _, mem := range members {
+		memPath := appendPath(linkPath, mem.GetFileName())
+		fmt.Fprintf(out, "%-*s- [`%s`](%s)\n", 2*depth, "", mem.GetName(), flatPageName(path.Join(memPath...)))
+	}
+}
+
+func (f *Wiki) ToFilePath(p string, kind string) string {
+	if len(p) == 0 {
+		return p
+	}
+	return flatPageName(p) + ".md"
+}
+
+func (f *Wiki) ToLinkPath(p string, kind string) string {
+	return wikiLinkPrefix + p
+}
+
+// ToURL converts a member path to the name of its wiki page, which is the page's URL relative to the wiki root.
+func (f *Wiki) ToURL(p string, kind string) string {
+	return flatPageName(p)
+}
+
+func (f *Wiki) Input(in string, sources []document.PackageSource) string {
+	return in
+}
+
+func (f *Wiki) Output(out string) string {
+	return out
+}
+
+func (f *Wiki) GitIgnore(in, out string, sources []document.PackageSource) []string {
+	return []string{
+		"# files generated by 'mojo doc'",
+		fmt.Sprintf("/%s/*.json", in),
+		"# wiki repository, with files generated by Modo",
+		fmt.Sprintf("/%s/", out),
+		"# test file generated by Modo",
+		"/test/",
+	}
+}
+
+func (f *Wiki) CreateDirs(base, in, out string, sources []document.PackageSource, _ *template.Template) error {
+	inDir, outDir := path.Join(base, in), path.Join(base, out)
+	testDir := path.Join(base, "test")
+	if err := util.MkDirs(inDir); err != nil {
+		return err
+	}
+	if err := util.MkDirs(outDir); err != nil {
+		return err
+	}
+	return util.MkDirs(testDir)
+}
+
+// Clean removes all files from the output directory, except hidden ones like the wiki's .git folder.
+func (f *Wiki) Clean(out, tests string) error {
+	entries, err := os.ReadDir(out)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if err := os.RemoveAll(path.Join(out, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return document.CleanDocTests(tests)
+}
+
+// flatPageName flattens a member path to a page name, for formats without directories.
+func flatPageName(p string) string {
+	return strings.ReplaceAll(p, "/", ".")
+}
+
+// flatLinkTarget resolves a link relative to a page's directory to a flattened page name.
+// Argument baseDir is the directory that contains the flattened pages.
+func flatLinkTarget(dir, link, baseDir string) string {
+	target := path.Join(dir, link)
+	if baseDir != "." {
+		target = strings.TrimPrefix(target, baseDir+"/")
+	}
+	return flatPageName(target)
+}
+
+func appendPath(p []string, elem string) []string {
+	newPath := append([]string{}, p...)
+	return append(newPath, elem)
+}
+
+// parseWikiParts extracts the package parts from a sidebar created by Modo.
+func parseWikiParts(text string) []wikiPart {
+	parts := []wikiPart{}
+	var current *wikiPart
+	content := strings.Builder{}
+
+	finish := func() {
+		if current != nil {
+			current.Content = strings.TrimSpace(content.String()) + "\n"
+			parts = append(parts, *current)
+		}
+		content.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := wikiPartRegex.FindStringSubmatch(line); m != nil {
+			finish()
+			current = &wikiPart{Title: m[1], Link: m[2]}
+			continue
+		}
+		if current != nil {
+			content.WriteString(line)
+			content.WriteRune('\n')
+		}
+	}
+	finish()
+
+	return parts
+}
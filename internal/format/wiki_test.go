@@ -0,0 +1,157 @@
+package format
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWikiToFilePath(t *testing.T) {
+	f := Wiki{}
+
+	text := f.ToFilePath("pkg/mod/Struct", "struct")
+	assert.Equal(t, text, "pkg.mod.Struct.md")
+
+	text = f.ToFilePath("pkg/mod", "module")
+	assert.Equal(t, text, "pkg.mod.md")
+
+	text = f.ToFilePath("pkg", "package")
+	assert.Equal(t, text, "pkg.md")
+}
+
+func TestWikiProcessPage(t *testing.T) {
+	f := Wiki{}
+
+	docs := document.Docs{
+		Decl: &document.Package{
+			MemberName: document.MemberName{Name: "pkg"},
+			MemberKind: document.MemberKind{Kind: "package"},
+		},
+	}
+	proc := document.NewProcessor(&docs, &f, nil, &document.Config{OutputDir: "out"})
+	proc.ExportDocs = &docs
+
+	text, err := f.ProcessPage(
+		"See [`Struct`](wiki:Struct), [`method`](wiki:../Trait#method) and [docs](https://example.com).\n",
+		"pkg/mod", proc)
+	assert.Nil(t, err)
+	assert.Equal(t,
+		"See [`Struct`](pkg.mod.Struct), [`method`](pkg.Trait#method) and [docs](https://example.com).\n",
+		text)
+}
+
+func TestWikiWriteAuxiliary(t *testing.T) {
+	f := Wiki{}
+
+	docs := document.Docs{
+		Decl: &document.Package{
+			MemberName: document.MemberName{Name: "pkg"},
+			MemberKind: document.MemberKind{Kind: "package"},
+
+			Modules: []*document.Module{
+				{
+					MemberName: document.MemberName{Name: "mod"},
+					MemberKind: document.MemberKind{Kind: "module"},
+					Structs: []*document.Struct{
+						{
+							MemberName: document.MemberName{Name: "Struct"},
+							MemberKind: document.MemberKind{Kind: "struct"},
+						},
+					},
+				},
+			},
+			Packages: []*document.Package{
+				{
+					MemberName: document.MemberName{Name: "subpkg"},
+					MemberKind: document.MemberKind{Kind: "package"},
+				},
+			},
+			Functions: []*document.Function{
+				{
+					MemberName: document.MemberName{Name: "func"},
+					MemberKind: document.MemberKind{Kind: "function"},
+				},
+			},
+		},
+	}
+
+	outDir := t.TempDir()
+	assert.Nil(t, os.WriteFile(path.Join(outDir, "_Sidebar.md"),
+		[]byte("**[Home](Home)**\n\n### [`other`](other)\n\n- [`mod`](other.mod)\n\n### [`pkg`](pkg)\n\n- [`old`](pkg.old)\n"), 0644))
+
+	templ, err := document.LoadTemplates(&f, "")
+	assert.Nil(t, err)
+
+	proc := document.NewProcessor(&docs, &f, templ, &document.Config{OutputDir: outDir})
+	proc.ExportDocs = &docs
+
+	err = f.WriteAuxiliary(docs.Decl, outDir, proc)
+	assert.Nil(t, err)
+
+	content, err := os.ReadFile(path.Join(outDir, "_Sidebar.md"))
+	assert.Nil(t, err)
+	assert.Equal(t, "**[Home](Home)**\n"+
+		"\n"+
+		"### [`other`](other)\n"+
+		"\n"+
+		"- [`mod`](other.mod)\n"+
+		"\n"+
+		"### [`pkg`](pkg)\n"+
+		"\n"+
+		"- [`subpkg`](pkg.subpkg)\n"+
+		"- [`mod`](pkg.mod)\n"+
+		"  - [`Struct`](pkg.mod.Struct)\n"+
+		"- [`func`](pkg.func)\n"+
+		"\n", string(content))
+
+	content, err = os.ReadFile(path.Join(outDir, "Home.md"))
+	assert.Nil(t, err)
+	assert.Equal(t, "# API reference\n"+
+		"\n"+
+		"- [`other`](other)\n"+
+		"- [`pkg`](pkg)\n", string(content))
+}
+
+func TestWikiClean(t *testing.T) {
+	f := Wiki{}
+
+	dir := t.TempDir()
+	outDir, testDir := path.Join(dir, "out"), path.Join(dir, "test")
+	assert.Nil(t, os.MkdirAll(path.Join(outDir, ".git"), os.ModePerm))
+	assert.Nil(t, os.MkdirAll(testDir, os.ModePerm))
+	assert.Nil(t, os.WriteFile(path.Join(outDir, "pkg.md"), []byte("# pkg\n"), 0644))
+
+	assert.Nil(t, f.Clean(outDir, testDir))
+
+	entries, err := os.ReadDir(outDir)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, ".git", entries[0].Name())
+}
+
+func TestWikiPageNameCollision(t *testing.T) {
+	yml := `
+decl:
+  name: pkg
+  kind: package
+  modules:
+    - name: x
+      kind: module
+  functions:
+    - name: x
+      kind: function
+      overloads:
+        - name: x
+          kind: function
+`
+	docs, err := document.FromYAML([]byte(yml))
+	assert.Nil(t, err)
+
+	config := document.Config{OutputDir: t.TempDir(), DryRun: true}
+	err = document.Render(docs, &config, &Wiki{}, "")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), " - pkg.x.md: module pkg.x, function pkg.x")
+}
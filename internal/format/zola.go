@@ -0,0 +1,209 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/mlange-42/modo/assets"
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/mlange-42/modo/internal/util"
+)
+
+const landingPageContentZola = `+++
+title = "Landing page"
++++
+
+JSON created by mojo doc should be placed next to this file.
+
+Additional documentation files go here, too.
+They will be processed for doc-tests and copied to folder 'site/content'.
+`
+
+const zolaLinkPrefix = "@/"
+
+var zolaLinkRegex = regexp.MustCompile(`\]\(@/([^)#\s]*)(#[^)\s]*)?\)`)
+
+type Zola struct{}
+
+func (f *Zola) Accepts(files []string) error {
+	return nil
+}
+
+func (f *Zola) ProcessMarkdown(element any, text string, proc *document.Processor) (string, error) {
+	b := strings.Builder{}
+	err := proc.Template.ExecuteTemplate(&b, "zola_front_matter.md", element)
+	if err != nil {
+		return "", err
+	}
+	b.WriteRune('\n')
+	b.WriteString(text)
+	return b.String(), nil
+}
+
+// ProcessPage turns relative internal links into links relative to the content directory,
+// and adapts anchors to Zola's slugs.
+func (f *Zola) ProcessPage(text string, dir string, proc *document.Processor) (string, error) {
+	return f.ProcessLinks(text, dir, proc)
+}
+
+// ProcessLinks turns relative internal links into links relative to the content directory,
+// and adapts anchors to Zola's slugs.
+func (f *Zola) ProcessLinks(text string, dir string, proc *document.Processor) (string, error) {
+	return zolaLinkRegex.ReplaceAllStringFunc(text, func(link string) string {
+		parts := zolaLinkRegex.FindStringSubmatch(link)
+		target := path.Join(dir, parts[1])
+		anchor := ""
+		if len(parts[2]) > 1 {
+			anchor = "#" + zolaSlug(parts[2][1:])
+		}
+		return fmt.Sprintf("](%s%s%s)", zolaLinkPrefix, target, anchor)
+	}), nil
+}
+
+func (f *Zola) WriteAuxiliary(p *document.Package, dir string, proc *document.Processor) error {
+	return nil
+}
+
+func (f *Zola) ToFilePath(p string, kind string) string {
+	if kind == "package" || kind == "module" {
+		return path.Join(p, "_index.md")
+	}
+	if len(p) == 0 {
+		return p
+	}
+	return p + ".md"
+}
+
+func (f *Zola) ToLinkPath(p string, kind string) string {
+	return zolaLinkPrefix + f.ToFilePath(p, kind)
+}
+
+// ToURL converts a member path to the URL of its page, following Zola's default slugified paths.
+func (f *Zola) ToURL(p string, kind string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		if part != "." && part != ".." {
+			parts[i] = zolaSlug(part)
+		}
+	}
+	return strings.Join(parts, "/") + "/"
+}
+
+func (f *Zola) Input(in string, sources []document.PackageSource) string {
+	return in
+}
+
+func (f *Zola) Output(out string) string {
+	return path.Join(out, "content")
+}
+
+func (f *Zola) GitIgnore(in, out string, sources []document.PackageSource) []string {
+	return []string{
+		"# files generated by 'mojo doc'",
+		fmt.Sprintf("/%s/*.json", in),
+		"# files generated by Modo",
+		fmt.Sprintf("/%s/%s/", out, "content"),
+		"# files generated by Zola",
+		fmt.Sprintf("/%s/%s/", out, "public"),
+		"# test file generated by Modo",
+		"/test/",
+	}
+}
+
+func (f *Zola) CreateDirs(base, in, out string, sources []document.PackageSource, templ *template.Template) error {
+	inDir, outDir := path.Join(base, in), path.Join(base, f.Output(out))
+	testDir := path.Join(base, "test")
+	if err := util.MkDirs(inDir); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path.Join(inDir, "_index.md"), []byte(landingPageContentZola), 0644); err != nil {
+		return err
+	}
+	if err := util.MkDirs(outDir); err != nil {
+		return err
+	}
+	if err := util.MkDirs(testDir); err != nil {
+		return err
+	}
+	return f.createInitialFiles(base, path.Join(base, out), templ)
+}
+
+func (f *Zola) createInitialFiles(docDir, zolaDir string, templ *template.Template) error {
+	config, err := document.GetGitOrigin(docDir)
+	if err != nil {
+		return err
+	}
+
+	outFile := path.Join(zolaDir, "config.toml")
+	exists, _, err := util.FileExists(outFile)
+	if err != nil {
+		return err
+	}
+	if exists {
+		fmt.Printf("WARNING: Zola config file %s already exists, skip creating\n", outFile)
+		return nil
+	}
+
+	b := bytes.Buffer{}
+	if err := templ.ExecuteTemplate(&b, "zola.toml", config); err != nil {
+		return err
+	}
+	if err := os.WriteFile(outFile, b.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	templDir := path.Join(zolaDir, "templates")
+	if err := util.MkDirs(templDir); err != nil {
+		return err
+	}
+	layouts, err := fs.ReadDir(assets.Zola, "zola")
+	if err != nil {
+		return err
+	}
+	for _, layout := range layouts {
+		content, err := fs.ReadFile(assets.Zola, path.Join("zola", layout.Name()))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path.Join(templDir, layout.Name()), content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Zola) Clean(out, tests string) error {
+	if err := emptyDir(out); err != nil {
+		return err
+	}
+	return document.CleanDocTests(tests)
+}
+
+// Slug converts a heading to Zola's anchor.
+func (f *Zola) Slug(heading string) string {
+	return zolaSlug(heading)
+}
+
+// zolaSlug mimics Zola's default slugification of heading anchors and paths.
+func zolaSlug(s string) string {
+	b := strings.Builder{}
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteRune('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package format
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/modo/internal/document"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZolaToFilePath(t *testing.T) {
+	f := Zola{}
+
+	text := f.ToFilePath("pkg/mod/Struct", "struct")
+	assert.Equal(t, text, "pkg/mod/Struct.md")
+
+	text = f.ToFilePath("pkg/mod", "module")
+	assert.Equal(t, text, "pkg/mod/_index.md")
+
+	text = f.ToFilePath("pkg", "package")
+	assert.Equal(t, text, "pkg/_index.md")
+}
+
+func TestZolaToLinkPath(t *testing.T) {
+	f := Zola{}
+
+	text := f.ToLinkPath("pkg/mod/Struct", "struct")
+	assert.Equal(t, text, "@/pkg/mod/Struct.md")
+
+	text = f.ToLinkPath("pkg", "package")
+	assert.Equal(t, text, "@/pkg/_index.md")
+}
+
+func TestZolaProcessMarkdown(t *testing.T) {
+	form := Zola{}
+	templ, err := document.LoadTemplates(&form, "")
+	assert.Nil(t, err)
+
+	proc := document.NewProcessor(nil, &form, templ, &document.Config{})
+
+	text, err := form.ProcessMarkdown(document.Struct{
+		MemberName: document.MemberName{Name: "Struct"},
+		MemberKind: document.MemberKind{Kind: "struct"},
+	}, "test", proc)
+	assert.Nil(t, err)
+
+	assert.Equal(t,
+		strings.ReplaceAll(text, "\r\n", "\n"),
+		`+++
+title = "Struct"
+weight = 100
++++
+
+test`)
+}
+
+func TestZolaProcessPage(t *testing.T) {
+	form := Zola{}
+	proc := document.NewProcessor(nil, &form, nil, &document.Config{})
+
+	text, err := form.ProcessPage(
+		"A [`Struct`](@/Struct.md), a [`Struct.__init__`](@/Struct.md#__init__), "+
+			"a [`Trait`](@/../Trait.md), the [`pkg`](@/../_index.md) and a [Markdown](link).",
+		"pkg/mod", proc)
+	assert.Nil(t, err)
+
+	assert.Equal(t,
+		"A [`Struct`](@/pkg/mod/Struct.md), a [`Struct.__init__`](@/pkg/mod/Struct.md#init), "+
+			"a [`Trait`](@/pkg/Trait.md), the [`pkg`](@/pkg/_index.md) and a [Markdown](link).",
+		text)
+}
+
+func TestZolaOutput(t *testing.T) {
+	f := Zola{}
+	assert.Equal(t, f.Output("site"), "site/content")
+}
+
+func TestZolaGitIgnore(t *testing.T) {
+	f := Zola{}
+	gi := f.GitIgnore("src", "site", []document.PackageSource{})
+
+	assert.Contains(t, gi, "/src/*.json")
+	assert.Contains(t, gi, "/site/content/")
+	assert.Contains(t, gi, "/site/public/")
+	assert.Contains(t, gi, "/test/")
+}
+
+func TestZolaCreateDirs(t *testing.T) {
+	f := Zola{}
+	testCreateDirs(&f, t, "docs/site/content", []string{
+		".",
+		"docs",
+		"docs/site",
+		"docs/site/config.toml",
+		"docs/site/content",
+		"docs/site/templates",
+		"docs/site/templates/base.html",
+		"docs/site/templates/index.html",
+		"docs/site/templates/page.html",
+		"docs/site/templates/section.html",
+		"docs/src",
+		"docs/src/_index.md",
+		"docs/test",
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 func MkDirs(path string) error {
@@ -33,3 +34,8 @@ func FileExists(file string) (exists, isDir bool, err error) {
 	err = nil
 	return
 }
+
+// ShellQuote quotes a string for use as a single argument in a POSIX shell command.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -36,3 +36,8 @@ func TestGetCwdName(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "util", name)
 }
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, "'my dir'", util.ShellQuote("my dir"))
+	assert.Equal(t, `'it'\''s'`, util.ShellQuote("it's"))
+}